@@ -0,0 +1,190 @@
+package mydject
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+)
+
+type (
+	// Hook はアプリケーションの開始時と停止時に呼び出される関数です。nil の関数は呼び出されません
+	Hook struct {
+		OnStart func(ctx context.Context) error
+		OnStop  func(ctx context.Context) error
+	}
+	// Lifecycle はアプリケーションに Hook を追加します。Application のコンテナで解決できます。
+	// コンストラクタの中で追加した Hook は依存関係の順に開始され、逆順に停止されます
+	Lifecycle interface {
+		Append(hook Hook)
+	}
+	lifecycle struct {
+		mu    sync.Mutex
+		hooks []Hook
+	}
+	// ApplicationOptions はアプリケーションの生成オプションです
+	ApplicationOptions struct {
+		// StartTimeout は Run で全ての OnStart を呼び出すまでのタイムアウトです。既定は 15 秒です
+		StartTimeout time.Duration
+		// StopTimeout は Run で全ての OnStop を呼び出すまでのタイムアウトです。既定は 15 秒です
+		StopTimeout time.Duration
+		// Signals は Run が停止を待つシグナルです。既定は SIGINT と SIGTERM です
+		Signals []os.Signal
+	}
+	// Application はコンテナで構築したアプリケーションを開始し、停止します
+	Application struct {
+		container Container
+		invoker   Invoker
+		lifecycle *lifecycle
+		options   ApplicationOptions
+		mu        sync.Mutex
+		// started は OnStart を呼び出した Hook を開始した順に保持します
+		started []Hook
+	}
+)
+
+const (
+	defaultHookTimeout = 15 * time.Second
+	hookOnStart        = "OnStart"
+	hookOnStop         = "OnStop"
+)
+
+var lifecycleType = As[Lifecycle]()
+
+// NewApplication はアプリケーションを生成し、コンテナに Lifecycle を登録します。
+// invoker はアプリケーションの開始時に Invoke され、ルートとなるコンポーネントを解決します。
+// Hook を追加するコンポーネントは ContainerManaged で登録してください
+func NewApplication(c Container, invoker Invoker, options ...ApplicationOptions) (*Application, error) {
+	if len(options) > 1 {
+		return nil, localize(languageOf(c), ErrNoMultipleOption)
+	}
+	var opts ApplicationOptions
+	if len(options) == 1 {
+		opts = options[0]
+	}
+	if opts.StartTimeout <= 0 {
+		opts.StartTimeout = defaultHookTimeout
+	}
+	if opts.StopTimeout <= 0 {
+		opts.StopTimeout = defaultHookTimeout
+	}
+	if len(opts.Signals) == 0 {
+		opts.Signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	l := &lifecycle{}
+	if err := c.Register(l, RegisterOptions{Interfaces: []reflect.Type{lifecycleType}}); err != nil {
+		return nil, err
+	}
+	return &Application{container: c, invoker: invoker, lifecycle: l, options: opts}, nil
+}
+
+// Append は Hook を追加します
+func (l *lifecycle) Append(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook)
+}
+func (l *lifecycle) snapshot() []Hook {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Hook{}, l.hooks...)
+}
+
+// Run はアプリケーションを開始し、シグナルを受け取るまで待ってから停止します。
+// 開始に失敗した場合は開始済みの Hook を停止し、開始のエラーを返します
+func (a *Application) Run() error {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, a.options.Signals...)
+	defer signal.Stop(sig)
+
+	startCtx, cancel := context.WithTimeout(context.Background(), a.options.StartTimeout)
+	err := a.Start(startCtx)
+	cancel()
+	if err == nil {
+		<-sig
+	}
+	stopCtx, cancel := context.WithTimeout(context.Background(), a.options.StopTimeout)
+	defer cancel()
+	if stopErr := a.Stop(stopCtx); err == nil {
+		err = stopErr
+	}
+	return err
+}
+
+// Start はルートとなるコンポーネントを解決し、追加された Hook の OnStart を依存関係の順に呼び出します。
+// OnStart がエラーを返すか ctx が終了した場合は HookError を返します。失敗した場合も Stop を呼び出してください
+func (a *Application) Start(ctx context.Context) error {
+	if err := a.container.Invoke(a.invoker); err != nil {
+		return err
+	}
+	for _, hook := range a.lifecycle.snapshot() {
+		if hook.OnStart != nil {
+			if err := a.runHook(ctx, hookOnStart, hook.OnStart); err != nil {
+				return err
+			}
+		}
+		a.mu.Lock()
+		a.started = append(a.started, hook)
+		a.mu.Unlock()
+	}
+	return nil
+}
+
+// Stop は開始した Hook の OnStop を開始の逆順に呼び出し、コンテナを Close します。
+// 全ての OnStop を呼び出し、失敗したものを StopError にまとめて返します
+func (a *Application) Stop(ctx context.Context) error {
+	a.mu.Lock()
+	started := a.started
+	a.started = nil
+	a.mu.Unlock()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		if started[i].OnStop == nil {
+			continue
+		}
+		if err := a.runHook(ctx, hookOnStop, started[i].OnStop); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := a.container.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return &StopError{Errors: errs, lang: languageOf(a.container)}
+	}
+	return nil
+}
+
+// runHook は Hook を呼び出し、ctx が終了した場合は Hook の終了を待たずにエラーを返します
+func (a *Application) runHook(ctx context.Context, event string, fn func(ctx context.Context) error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err == nil {
+		return nil
+	}
+	name, file, line := getFuncLocation(reflect.ValueOf(fn))
+	return &HookError{Event: event, Func: name, File: file, Line: line, Err: err, lang: languageOf(a.container)}
+}
+
+// languageOf はコンテナのエラーメッセージの言語を返します
+func languageOf(c ServiceLocator) Language {
+	switch c := c.(type) {
+	case *container:
+		return c.options.Language
+	case *scope:
+		return c.container.options.Language
+	}
+	return Japanese
+}
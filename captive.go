@@ -0,0 +1,239 @@
+package mydject
+
+import (
+	"log"
+	"reflect"
+)
+
+type (
+	// CaptiveDependencyPolicy はライフタイムスコープの長い登録が短い登録に依存している場合 (captive dependency) の扱いです。
+	// 短いライフタイムスコープのインスタンスが長いライフタイムスコープのインスタンスに保持され続けることを防ぎます
+	CaptiveDependencyPolicy int
+	// captiveDependency は依存関係 dependency より長く保持される consumer が dependency に依存している箇所です
+	captiveDependency struct {
+		consumer   key
+		dependency key
+	}
+	// registration は1回の Register または Decorate による登録の変更です。
+	// captive dependency でエラーとする場合に取り消せるように、変更前の状態を保持します
+	registration struct {
+		// keys は追加した登録のキーです。グループのメンバーを含みます
+		keys []key
+		// replaced は上書きした登録の変更前の値です。存在しなかった場合は nil です
+		replaced map[key]*factoryInfo
+		// groups は追加したグループの変更前のメンバー数です。グループが存在しなかった場合は -1 です
+		groups map[key]int
+		// decorated は Decorate でデコレータを追加した型です
+		decorated reflect.Type
+		// decorator は Decorate で追加したデコレータです
+		decorator decorator
+		// cache は取り除いたキャッシュです
+		cache map[key]*instance
+	}
+)
+
+func newRegistration() *registration {
+	return &registration{replaced: make(map[key]*factoryInfo), groups: make(map[key]int), cache: make(map[key]*instance)}
+}
+
+const (
+	// CaptiveDependencyFailOnVerify の場合、Verify でエラーとして検出します。既定です
+	CaptiveDependencyFailOnVerify CaptiveDependencyPolicy = iota
+	// CaptiveDependencyFailOnRegister の場合、Verify に加えて Register でもエラーとして検出し、その登録を取り消します
+	CaptiveDependencyFailOnRegister
+	// CaptiveDependencyWarn の場合、Register と Verify で検出した違反を ContainerOptions.OnWarning に通知し、エラーにはしません
+	CaptiveDependencyWarn
+)
+
+// outlives は s のインスタンスが d のインスタンスより長く保持されるかどうかを返します。
+// Transient なインスタンスは要求した側が所有するため、どちらの場合も対象外です
+func (s LifetimeScope) outlives(d LifetimeScope) bool {
+	rank := func(s LifetimeScope) int {
+		switch s {
+		case ContainerManaged:
+			return 3
+		case ScopeManaged:
+			return 2
+		case InvokeManaged:
+			return 1
+		}
+		return 0
+	}
+	if s == Transient || d == Transient {
+		return false
+	}
+	return rank(s) > rank(d)
+}
+
+// captiveDependencies は registry の中で、ライフタイムスコープの長い登録が短い登録に依存している箇所を全て返します。
+// Lazy や Provider などで遅延して解決される依存関係は対象外です
+func (c *container) captiveDependencies(registry *registry) []captiveDependency {
+	var captives []captiveDependency
+	for _, k := range registry.keys() {
+		f, _ := registry.get(k)
+		captives = append(captives, c.captivesOf(registry, k, f, registry.params(k, f))...)
+	}
+	return captives
+}
+
+// captivesOf は consumer の登録が params で依存している箇所のうち、captive dependency であるものを返します
+func (c *container) captivesOf(registry *registry, consumer key, f factoryInfo, params []param) []captiveDependency {
+	if !f.isFunc {
+		return nil
+	}
+	var captives []captiveDependency
+	for _, p := range params {
+		if c.isBuiltinType(p.key.t) {
+			continue
+		}
+		deps, ok, deferred := c.dependencyKeys(registry, p.key)
+		if !ok || deferred {
+			continue
+		}
+		for _, d := range deps {
+			dep, _ := registry.get(d)
+			if dep.isFunc && f.lifetimeScope.outlives(dep.lifetimeScope) {
+				captives = append(captives, captiveDependency{consumer: consumer, dependency: d})
+			}
+		}
+	}
+	return captives
+}
+
+// registeredCaptives は reg で追加した登録またはデコレータに関係する依存関係のうち、captive dependency であるものを返します。
+// 登録全体ではなく、追加した登録が依存している箇所と追加した登録に依存している箇所のみを検査します
+func (c *container) registeredCaptives(reg *registration) []captiveDependency {
+	var captives []captiveDependency
+	for _, k := range reg.keys {
+		f, _ := c.registry.get(k)
+		captives = append(captives, c.captivesOf(c.registry, k, f, c.registry.params(k, f))...)
+		for _, consumer := range c.registry.dependentsOf(k) {
+			cf, ok := c.registry.get(consumer)
+			if !ok {
+				continue
+			}
+			for _, captive := range c.captivesOf(c.registry, consumer, cf, c.registry.params(consumer, cf)) {
+				if captive.dependency == k {
+					captives = append(captives, captive)
+				}
+			}
+		}
+	}
+	if reg.decorated != nil {
+		for _, k := range c.registry.keysOf(reg.decorated) {
+			f, _ := c.registry.get(k)
+			captives = append(captives, c.captivesOf(c.registry, k, f, reg.decorator.params())...)
+		}
+	}
+	// 同じ依存関係を複数の経路から検出した場合は1つにまとめます
+	seen := make(map[captiveDependency]bool, len(captives))
+	unique := captives[:0]
+	for _, captive := range captives {
+		if !seen[captive] {
+			seen[captive] = true
+			unique = append(unique, captive)
+		}
+	}
+	return unique
+}
+
+// newLifetimeViolationError は captive dependency を報告するエラーを生成します
+func (c *container) newLifetimeViolationError(registry *registry, captive captiveDependency) error {
+	f, _ := registry.get(captive.consumer)
+	dep, _ := registry.get(captive.dependency)
+	return &LifetimeViolationError{
+		Type:                    captive.consumer.t,
+		LifetimeScope:           f.lifetimeScope,
+		Dependency:              captive.dependency.t,
+		DependencyLifetimeScope: dep.lifetimeScope,
+		lang:                    c.options.Language,
+	}
+}
+
+// verifyLifetimes は Verify で captive dependency を検出し、ポリシーに従ってエラーとして返すか通知します
+func (c *container) verifyLifetimes(registry *registry) []error {
+	var errs []error
+	for _, captive := range c.captiveDependencies(registry) {
+		errs = append(errs, c.newLifetimeViolationError(registry, captive))
+	}
+	if c.options.CaptiveDependency == CaptiveDependencyWarn {
+		c.warn(errs...)
+		return nil
+	}
+	return errs
+}
+
+// checkRegisteredLifetimes は reg の登録で発生した captive dependency を検出します。
+// エラーとする場合は reg の登録を取り消します。警告は c.mu のロックを解除してから warn で通知するため返します。c.mu をロックして呼び出します
+func (c *container) checkRegisteredLifetimes(target Target, reg *registration, err error) ([]error, error) {
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, captive := range c.registeredCaptives(reg) {
+		errs = append(errs, c.newLifetimeViolationError(c.registry, captive))
+	}
+	if len(errs) == 0 {
+		return nil, nil
+	}
+	if c.options.CaptiveDependency == CaptiveDependencyWarn {
+		return errs, nil
+	}
+	c.rollback(reg)
+	if len(errs) == 1 {
+		return nil, c.newRegistrationError(target, errs[0])
+	}
+	return nil, c.newRegistrationError(target, &VerifyError{Errors: errs, lang: c.options.Language})
+}
+
+// rollback は reg の登録を取り消し、取り除いたキャッシュを戻します。c.mu をロックして呼び出します
+func (c *container) rollback(reg *registration) {
+	for k, f := range reg.replaced {
+		if f == nil {
+			delete(c.registry.factoryInfos, k)
+		} else {
+			c.registry.factoryInfos[k] = *f
+		}
+	}
+	for g, n := range reg.groups {
+		if n < 0 {
+			delete(c.registry.groups, g)
+		} else {
+			c.registry.groups[g] = c.registry.groups[g][:n]
+		}
+	}
+	if reg.decorated != nil {
+		ds := c.registry.decorators[reg.decorated]
+		if len(ds) == 1 {
+			delete(c.registry.decorators, reg.decorated)
+		} else {
+			c.registry.decorators[reg.decorated] = ds[:len(ds)-1]
+		}
+	}
+	for k, inst := range reg.cache {
+		c.cache[k] = inst
+	}
+}
+
+// removeCache はキャッシュを取り除き、取り消せるように reg に記録します。c.mu をロックして呼び出します
+func (c *container) removeCache(reg *registration, k key) {
+	inst, ok := c.cache[k]
+	if !ok {
+		return
+	}
+	if _, ok := reg.cache[k]; !ok {
+		reg.cache[k] = inst
+	}
+	delete(c.cache, k)
+}
+
+// warn は警告を ContainerOptions.OnWarning に通知します。指定されていない場合はログに出力します
+func (c *container) warn(errs ...error) {
+	for _, err := range errs {
+		if c.options.OnWarning != nil {
+			c.options.OnWarning(err)
+		} else {
+			log.Printf("mydject: %v", err)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// mydject-proxy はインターフェイスのメソッド呼び出しをインターセプタに渡すプロキシを生成します。
+// 生成したプロキシは RegisterOptions.Interceptors を指定して登録したコンポーネントに使われます。
+//
+//	//go:generate go run github.com/ohishikaito/mydject/cmd/mydject-proxy -type Repository,Service
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const generatedHeader = "Code generated by mydject-proxy. DO NOT EDIT."
+
+func main() {
+	typeNames := flag.String("type", "", "プロキシを生成するインターフェイスの名前。カンマ区切りで複数指定できます")
+	output := flag.String("output", "", "出力するファイル。既定は <パッケージ名>_proxy.go です")
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	src, pkgName, err := generate(dir, strings.Split(*typeNames, ","))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "mydject-proxy:", err)
+		os.Exit(1)
+	}
+	if *output == "" {
+		*output = filepath.Join(dir, pkgName+"_proxy.go")
+	}
+	if err := os.WriteFile(*output, src, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "mydject-proxy:", err)
+		os.Exit(1)
+	}
+}
+
+// generate は dir のパッケージを型検査し、指定されたインターフェイスのプロキシのソースコードを返します
+func generate(dir string, typeNames []string) ([]byte, string, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(pkgs) != 1 {
+		return nil, "", fmt.Errorf("%s には1つのパッケージが必要です", dir)
+	}
+	var files []*ast.File
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			// 以前に生成したプロキシは生成し直すため型検査の対象から外します
+			if len(f.Comments) > 0 && strings.HasPrefix(f.Comments[0].Text(), generatedHeader) {
+				continue
+			}
+			files = append(files, f)
+		}
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check(absDir, fset, files, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	imports := make(map[string]string)
+	qualifier := func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		imports[p.Path()] = p.Name()
+		return p.Name()
+	}
+	var body bytes.Buffer
+	for _, name := range typeNames {
+		obj := pkg.Scope().Lookup(strings.TrimSpace(name))
+		if obj == nil {
+			return nil, "", fmt.Errorf("%s が見つかりません", name)
+		}
+		iface, ok := obj.Type().Underlying().(*types.Interface)
+		if !ok {
+			return nil, "", fmt.Errorf("%s はインターフェイスではありません", name)
+		}
+		if err := writeProxy(&body, obj.Name(), iface, qualifier); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// %s\n\npackage %s\n\n", generatedHeader, pkg.Name())
+	imports["github.com/ohishikaito/mydject"] = "mydject"
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	buf.WriteString("import (\n")
+	for _, path := range paths {
+		fmt.Fprintf(&buf, "\t%q\n", path)
+	}
+	buf.WriteString(")\n")
+	buf.Write(body.Bytes())
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, "", err
+	}
+	return src, pkg.Name(), nil
+}
+
+// writeProxy はインターフェイスの全てのメソッドを ProxyHandler.Call に渡すプロキシを書き込みます
+func writeProxy(w *bytes.Buffer, name string, iface *types.Interface, qualifier types.Qualifier) error {
+	proxy := unexport(name) + "Proxy"
+	fmt.Fprintf(w, "\n// %s は %s のメソッド呼び出しをインターセプタに渡すプロキシです\n", proxy, name)
+	fmt.Fprintf(w, "type %s struct {\n\thandler *mydject.ProxyHandler\n}\n", proxy)
+	fmt.Fprintf(w, "\nfunc init() {\n\tmydject.RegisterProxy[%s](func(h *mydject.ProxyHandler) %s {\n\t\treturn &%s{handler: h}\n\t})\n}\n", name, name, proxy)
+	for i := 0; i < iface.NumMethods(); i++ {
+		m := iface.Method(i)
+		if !m.Exported() {
+			return fmt.Errorf("%s.%s: 公開されていないメソッドのプロキシは生成できません", name, m.Name())
+		}
+		sig := m.Type().(*types.Signature)
+		params := make([]string, sig.Params().Len())
+		args := []string{fmt.Sprintf("%q", m.Name())}
+		for j := range params {
+			t := sig.Params().At(j).Type()
+			if sig.Variadic() && j == len(params)-1 {
+				params[j] = fmt.Sprintf("a%d ...%s", j, types.TypeString(t.(*types.Slice).Elem(), qualifier))
+			} else {
+				params[j] = fmt.Sprintf("a%d %s", j, types.TypeString(t, qualifier))
+			}
+			args = append(args, fmt.Sprintf("a%d", j))
+		}
+		results := make([]string, sig.Results().Len())
+		for j := range results {
+			results[j] = types.TypeString(sig.Results().At(j).Type(), qualifier)
+		}
+		fmt.Fprintf(w, "\n// %s は %s.%s の呼び出しをインターセプタに渡します\n", m.Name(), name, m.Name())
+		fmt.Fprintf(w, "func (p *%s) %s(%s) (%s) {\n", proxy, m.Name(), strings.Join(params, ", "), strings.Join(results, ", "))
+		call := fmt.Sprintf("p.handler.Call(%s)", strings.Join(args, ", "))
+		if len(results) == 0 {
+			fmt.Fprintf(w, "\t%s\n}\n", call)
+			continue
+		}
+		fmt.Fprintf(w, "\tresults := %s\n", call)
+		names := make([]string, len(results))
+		for j, t := range results {
+			names[j] = fmt.Sprintf("r%d", j)
+			fmt.Fprintf(w, "\t%s, _ := results[%d].(%s)\n", names[j], j, t)
+		}
+		fmt.Fprintf(w, "\treturn %s\n}\n", strings.Join(names, ", "))
+	}
+	return nil
+}
+
+func unexport(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
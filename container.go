@@ -1,41 +1,59 @@
 package mydject
 
 import (
+	"context"
 	"reflect"
+	"runtime/debug"
+	"sync"
 )
 
 type (
 	container struct {
-		factoryInfos                map[reflect.Type]factoryInfo
-		cache                       map[reflect.Type]reflect.Value
+		mu                          sync.RWMutex
+		registry                    *registry
+		cache                       map[key]*instance
+		created                     []createdInstance
 		containerInterfaceType      reflect.Type
 		ioCContainerInterfaceType   reflect.Type
 		serviceLocatorInterfaceType reflect.Type
+		options                     ContainerOptions
 	}
-	// Container は DIコンテナーです
+	// Container は DIコンテナーです。複数の goroutine から並行して利用できます
 	Container interface {
 		Register(constructor Target, options ...RegisterOptions) error
+		Decorate(decorator Target) error
+		Close() error
 		IoCContainer
 	}
 	// IoCContainer です
 	IoCContainer interface {
 		ServiceLocator
 		CreateChildContainer() Container
+		BeginScope() Scope
 	}
 	// ServiceLocator です
 	ServiceLocator interface {
 		Invoke(invoker Invoker) error
-		Verify() error
+		InvokeContext(ctx context.Context, invoker Invoker) error
+		Call(invoker Invoker) ([]reflect.Value, error)
+		ResolveNamed(name string, target interface{}) error
+		InjectInto(target interface{}) error
+		Verify(options ...VerifyOptions) error
 	}
 )
 
 // NewContainer はコンテナーを生成します
 func NewContainer(options ...ContainerOptions) Container {
-	return newContainer(make(map[reflect.Type]factoryInfo), make(map[reflect.Type]reflect.Value))
+	var opts ContainerOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	return newContainer(newRegistry(), make(map[key]*instance), opts)
 }
-func newContainer(factoryInfos map[reflect.Type]factoryInfo, cache map[reflect.Type]reflect.Value) *container {
+func newContainer(registry *registry, cache map[key]*instance, options ContainerOptions) *container {
 	return &container{
-		factoryInfos:                factoryInfos,
+		options:                     options,
+		registry:                    registry,
 		cache:                       cache,
 		containerInterfaceType:      reflect.TypeOf((*Container)(nil)).Elem(),
 		ioCContainerInterfaceType:   reflect.TypeOf((*IoCContainer)(nil)).Elem(),
@@ -45,25 +63,39 @@ func newContainer(factoryInfos map[reflect.Type]factoryInfo, cache map[reflect.T
 
 // CreateChildContainer は子コンテナを生成します
 func (c *container) CreateChildContainer() Container {
-	factoryInfos := make(map[reflect.Type]factoryInfo)
-	for key, value := range c.factoryInfos {
-		factoryInfos[key] = value
-	}
-	cache := make(map[reflect.Type]reflect.Value)
-	for key, value := range c.cache {
-		cache[key] = value
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cache := make(map[key]*instance)
+	for k, value := range c.cache {
+		// 生成途中のインスタンスは子コンテナの登録内容で生成されうるため引き継がない
+		if value.isCreated() {
+			cache[k] = value
+		}
 	}
-	return newContainer(factoryInfos, cache)
+	return newContainer(c.registry.clone(), cache, c.options)
 }
 
 // Register はコンストラクタまたは定数を登録します
-func (c *container) Register(target Target, options ...RegisterOptions) error {
+func (c *container) Register(target Target, options ...RegisterOptions) (err error) {
 	if len(options) > 1 {
-		return ErrNoMultipleOption
+		return c.newRegistrationError(target, ErrNoMultipleOption)
+	}
+	if isNilTarget(target) {
+		return c.newRegistrationError(target, ErrNilTarget)
 	}
 	out, ins, err := getTargetReflectionInfos(target)
 	if err != nil {
-		return err
+		return c.newRegistrationError(target, err)
+	}
+	if len(options) == 1 {
+		if err := c.validateInterfaces(out, options[0]); err != nil {
+			return c.newRegistrationError(target, err)
+		}
+		if len(options[0].Interceptors) > 0 {
+			if err := validateProxies(options[0].Interfaces); err != nil {
+				return c.newRegistrationError(target, err)
+			}
+		}
 	}
 	lts := InvokeManaged
 	kind := out.Kind()
@@ -72,63 +104,184 @@ func (c *container) Register(target Target, options ...RegisterOptions) error {
 		lts = ContainerManaged
 	}
 	count := 0
+	name := ""
+	group := false
+	// boundOut は返り値の型が Interfaces に含まれ、既に登録されている場合に true です
+	boundOut := false
 	value := reflect.ValueOf(target)
+	cleanupIndex := 0
+	var fields []param
+	var st reflect.Type
+	if value.Kind() == reflect.Func {
+		cleanupIndex = getCleanupIndex(value.Type())
+		if _, err := getArgParams(ins, value.Type().IsVariadic()); err != nil {
+			return c.newRegistrationError(target, err)
+		}
+	} else if isFunc {
+		// Struct で指定された構造体を登録します
+		st = out
+		t, _ := structType(out)
+		if fields, err = getInjectFields(t); err != nil {
+			return c.newRegistrationError(target, err)
+		}
+	}
+	// 警告の通知で OnWarning がコンテナを利用できるように、c.mu のロックを解除してから通知します
+	var warnings []error
+	defer func() {
+		c.warn(warnings...)
+	}()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	reg := newRegistration()
+	if c.options.CaptiveDependency != CaptiveDependencyFailOnVerify {
+		defer func() {
+			warnings, err = c.checkRegisteredLifetimes(target, reg, err)
+		}()
+	}
+	if isFunc && st == nil && embeds(out, outType) {
+		var option RegisterOptions
+		if len(options) == 1 {
+			option = options[0]
+			lts = option.LifetimeScope
+		}
+		outs, err := getOutFields(out, option)
+		if err != nil {
+			return c.newRegistrationError(target, err)
+		}
+		return c.registerOutputs(reg, target, factoryInfo{target: value, lifetimeScope: lts, ins: ins, isFunc: isFunc, cleanupIndex: cleanupIndex}, option, outs)
+	}
 	if len(options) == 1 {
 		option := options[0]
 		if isFunc {
 			lts = option.LifetimeScope
+			if option.MultipleOutputs && st == nil {
+				f := factoryInfo{target: value, lifetimeScope: lts, ins: ins, isFunc: isFunc, cleanupIndex: cleanupIndex}
+				return c.registerOutputs(reg, target, f, option, getOutputs(f, option))
+			}
 		}
+		name = option.Name
+		group = option.Group
 		if option.Interfaces != nil && len(option.Interfaces) > 0 {
 			for _, p := range option.Interfaces {
-				c.factoryInfos[p] = factoryInfo{target: value, lifetimeScope: lts, ins: ins, isFunc: isFunc}
-				_, ok := c.cache[p]
-				if ok {
-					delete(c.cache, p)
-				}
+				c.add(reg, key{t: p, name: name}, factoryInfo{target: value, lifetimeScope: lts, ins: ins, isFunc: isFunc, cleanupIndex: cleanupIndex, fields: fields, structType: st, interceptors: option.Interceptors}, group)
+				boundOut = boundOut || p == out
 				count++
 			}
 		}
 	}
-	if kind != reflect.Ptr {
-		c.factoryInfos[out] = factoryInfo{target: value, lifetimeScope: lts, ins: ins, isFunc: isFunc}
-		_, ok := c.cache[out]
-		if ok {
-			delete(c.cache, out)
-		}
+	if kind != reflect.Ptr && !boundOut {
+		c.add(reg, key{t: out, name: name}, factoryInfo{target: value, lifetimeScope: lts, ins: ins, isFunc: isFunc, cleanupIndex: cleanupIndex, fields: fields, structType: st}, group)
 		count++
-	} else if count == 0 {
-		return ErrNeedInterfaceOnPointerRegistering
+	} else if kind == reflect.Ptr && count == 0 {
+		return c.newRegistrationError(target, ErrNeedInterfaceOnPointerRegistering)
 	}
 	return nil
 }
 
+// validateInterfaces は Interfaces の各型がインターフェイスであり、登録する型が実装していることを検証します。
+// 複数の返り値を登録する場合は、実装している返り値があることを registerOutputs で検証し、同じ InterfaceError を返します
+func (c *container) validateInterfaces(out reflect.Type, option RegisterOptions) error {
+	outputs := option.MultipleOutputs || embeds(out, outType)
+	for _, p := range option.Interfaces {
+		var err error
+		if p == nil || p.Kind() != reflect.Interface {
+			err = ErrRequireInterface
+		} else if !outputs && !out.AssignableTo(p) {
+			err = ErrNotImplemented
+		}
+		if err != nil {
+			return &InterfaceError{Type: out, Interface: p, Err: c.localize(err), lang: c.options.Language}
+		}
+	}
+	return nil
+}
+
+// add は登録を追加し、上書きされた登録のキャッシュを取り除きます。取り消せるように変更前の状態を reg に記録します。c.mu をロックして呼び出します
+func (c *container) add(reg *registration, k key, f factoryInfo, group bool) {
+	if group {
+		if _, ok := reg.groups[k]; !ok {
+			n := -1
+			if members, ok := c.registry.groups[k]; ok {
+				n = len(members)
+			}
+			reg.groups[k] = n
+		}
+	} else if _, ok := reg.replaced[k]; !ok {
+		var prev *factoryInfo
+		if f, ok := c.registry.factoryInfos[k]; ok {
+			prev = &f
+		}
+		reg.replaced[k] = prev
+	}
+	k = c.registry.add(k, f, group)
+	reg.keys = append(reg.keys, k)
+	c.removeCache(reg, k)
+	c.removeCache(reg, k.decoratedKey())
+}
+
 // Invoke はコンテナからインスタンスを解決して呼び出します
 func (c *container) Invoke(invoker Invoker) error {
+	return c.invoke(invoker, newInvocation(context.Background()))
+}
+
+// InvokeContext は ctx を使ってコンテナからインスタンスを解決して呼び出します。
+// context.Context を要求したコンストラクタには ctx が渡され、ctx がキャンセルされると解決を中止して ctx.Err() を返します。
+// ctx が WithScope でスコープを保持している場合はスコープの中で解決します
+func (c *container) InvokeContext(ctx context.Context, invoker Invoker) error {
+	return c.invoke(invoker, newInvocation(ctx))
+}
+func (c *container) invoke(invoker Invoker, inv *invocation) error {
+	_, err := c.call(invoker, inv)
+	return err
+}
+
+// Call はコンテナからインスタンスを解決して呼び出し、invoker の返り値を返します。
+// 最後の返り値の型が error の場合は返り値から取り除き、エラーとして返します
+func (c *container) Call(invoker Invoker) ([]reflect.Value, error) {
+	return c.call(invoker, newInvocation(context.Background()))
+}
+func (c *container) call(invoker Invoker, inv *invocation) ([]reflect.Value, error) {
 	t := reflect.TypeOf(invoker)
-	if t.Kind() != reflect.Func {
-		return ErrRequireFunction
+	if t == nil || t.Kind() != reflect.Func {
+		return nil, c.localize(ErrRequireFunction)
 	}
-	ins := getIns(t)
-	lenIns := len(ins)
-	if lenIns == 0 {
-		return ErrNotFoundComponent
+	args, err := c.resolveArgs(getIns(t), t.IsVariadic(), inv)
+	if err != nil {
+		return nil, c.cleanup(inv, err)
 	}
-	args := make([]reflect.Value, lenIns)
-	cache := make(map[reflect.Type]reflect.Value)
-	for i, in := range ins {
-		v, err := c.resolve(in, &cache)
-		if err != nil {
-			return err
-		}
-		args[i] = *v
+	if err := inv.ctx.Err(); err != nil {
+		return nil, c.cleanup(inv, err)
 	}
 
 	fn := reflect.ValueOf(invoker)
-	outs := fn.Call(args)
-	if err := c.getError(outs); err != nil {
+	outs, err := c.callRecover(t, fn, args)
+	if err != nil {
+		return nil, c.cleanup(inv, err)
+	}
+	err = c.getError(outs)
+	if l := len(outs); l > 0 && t.Out(l-1) == errorType {
+		outs = outs[:l-1]
+	}
+	return outs, c.cleanup(inv, err)
+}
+
+// cleanup は Invoke の終了時に InvokeManaged なインスタンスの後処理を生成の逆順に実行します。
+// 後処理でエラーが発生した場合は err と合わせて CloseError として返します
+func (c *container) cleanup(inv *invocation, err error) error {
+	var errs []error
+	cleanups := inv.finish()
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		if cerr := cleanups[i](); cerr != nil {
+			errs = append(errs, cerr)
+		}
+	}
+	if len(errs) == 0 {
 		return err
 	}
-	return nil
+	if err != nil {
+		errs = append([]error{err}, errs...)
+	}
+	return &CloseError{Errors: errs, lang: c.options.Language}
 }
 
 func (c *container) getError(outs []reflect.Value) error {
@@ -141,95 +294,392 @@ func (c *container) getError(outs []reflect.Value) error {
 	return nil
 }
 
-func (c *container) resolve(t reflect.Type, cache *map[reflect.Type]reflect.Value) (*reflect.Value, error) {
-	if c.containerInterfaceType == t || c.ioCContainerInterfaceType == t || c.serviceLocatorInterfaceType == t {
+func (c *container) isSelfType(t reflect.Type) bool {
+	return c.containerInterfaceType == t || c.ioCContainerInterfaceType == t || c.serviceLocatorInterfaceType == t
+}
+
+// isBuiltinType は登録せずに解決できる型かどうかを返します
+func (c *container) isBuiltinType(t reflect.Type) bool {
+	return c.isSelfType(t) || t == contextType
+}
+
+// ResolveNamed は名前を指定して登録されたコンポーネントを解決し、target が指す変数に設定します
+func (c *container) ResolveNamed(name string, target interface{}) error {
+	return c.resolveNamed(name, target, newInvocation(context.Background()))
+}
+func (c *container) resolveNamed(name string, target interface{}, inv *invocation) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return c.localize(ErrRequirePointer)
+	}
+	resolved, err := c.resolve(key{t: v.Type().Elem(), name: name}, inv)
+	if err == nil {
+		v.Elem().Set(*resolved)
+	}
+	return c.cleanup(inv, err)
+}
+
+// isRegistered は解決できる登録が存在するかどうかを返します
+func (c *container) isRegistered(k key) bool {
+	if c.isBuiltinType(k.t) {
+		return true
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if _, ok := c.registry.get(k); ok {
+		return true
+	}
+	_, ok := c.registry.members(k)
+	return ok
+}
+
+func (c *container) resolve(k key, inv *invocation) (*reflect.Value, error) {
+	if k.t == contextType {
+		v := reflect.ValueOf(&inv.ctx).Elem()
+		return &v, nil
+	}
+	if c.isSelfType(k.t) {
 		v := reflect.ValueOf(c)
+		if inv.scope != nil && k.t == c.serviceLocatorInterfaceType {
+			// スコープの中では ServiceLocator としてスコープを解決します
+			v = reflect.ValueOf(inv.scope)
+		}
+		return &v, nil
+	}
+	c.mu.RLock()
+	factoryInfo, ok := c.registry.get(k)
+	members, isGroup := c.registry.members(k)
+	c.mu.RUnlock()
+	if !ok && isGroup {
+		return c.resolveGroup(k, members, inv)
+	}
+	if w, isWrapper := asWrapper(k.t); !ok && isWrapper {
+		v, err := w.resolveWrapper(c, k, inv)
+		if err != nil {
+			return nil, err
+		}
+		return &v, nil
+	}
+	if w, isWrapper := asProviderFunc(k.t); !ok && isWrapper {
+		v, err := w.resolveWrapper(c, k, inv)
+		if err != nil {
+			return nil, err
+		}
 		return &v, nil
 	}
-	factoryInfo, ok := c.factoryInfos[t]
 	if !ok {
-		return nil, newErrInvalidResolveComponent(t)
+		return nil, c.newResolveError(append(inv.path, k), c.localize(ErrInvalidResolveComponent))
 	}
+	return c.resolveFactory(k, factoryInfo, inv)
+}
+
+// resolveGroup はグループのメンバーを登録順に解決してスライスにします
+func (c *container) resolveGroup(k key, members []key, inv *invocation) (*reflect.Value, error) {
+	values := reflect.MakeSlice(k.t, 0, len(members))
+	for _, m := range members {
+		c.mu.RLock()
+		factoryInfo, ok := c.registry.get(m)
+		c.mu.RUnlock()
+		if !ok {
+			return nil, c.newResolveError(append(inv.path, m), c.localize(ErrInvalidResolveComponent))
+		}
+		v, err := c.resolveFactory(m, factoryInfo, inv)
+		if err != nil {
+			return nil, err
+		}
+		values = reflect.Append(values, *v)
+	}
+	return &values, nil
+}
+
+// resolveFactory は循環参照を検出し、登録にデコレータとプロキシを適用してからライフタイムスコープに従って解決します。
+// ctx がキャンセルされている場合は解決を中止します
+func (c *container) resolveFactory(k key, factoryInfo factoryInfo, inv *invocation) (*reflect.Value, error) {
+	if err := inv.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if inv.isResolving(k) {
+		path := append(inv.path, k)
+		return nil, c.newResolveError(path, &CircularDependencyError{Path: c.newDependencies(path), lang: c.options.Language})
+	}
+	inv.push(k)
+	defer inv.pop()
+	c.mu.RLock()
+	decorators := c.registry.decorators[k.t]
+	c.mu.RUnlock()
+	if len(decorators) > 0 || len(factoryInfo.interceptors) > 0 {
+		factoryInfo = factoryInfo.decorate(decorators)
+	}
+	return c.resolveLifetime(k, factoryInfo, inv)
+}
+
+// resolveLifetime は登録されたライフタイムスコープに従ってインスタンスを解決します
+func (c *container) resolveLifetime(k key, factoryInfo factoryInfo, inv *invocation) (*reflect.Value, error) {
 	switch factoryInfo.lifetimeScope {
 	case ContainerManaged:
-		return c.resolveContainerManagedObject(t, factoryInfo, cache)
+		return c.resolveCachedObject(c, k, factoryInfo, inv)
+	case ScopeManaged:
+		if inv.scope == nil {
+			return nil, c.newResolveError(inv.path, c.localize(ErrRequireScope))
+		}
+		return c.resolveCachedObject(inv.scope, k, factoryInfo, inv)
+	case Transient:
+		return c.resolveTransientObject(k, factoryInfo, inv)
 	}
-	return c.resolveInvokeManagedObject(t, factoryInfo, cache)
+	return c.resolveInvokeManagedObject(k, factoryInfo, inv)
 }
-func (c *container) resolveContainerManagedObject(t reflect.Type, factoryInfo factoryInfo, cache *map[reflect.Type]reflect.Value) (*reflect.Value, error) {
-	if v, ok := c.cache[t]; ok {
+
+// resolveCachedObject は store にキャッシュされたインスタンスを解決し、存在しない場合は生成して store に記録します
+func (c *container) resolveCachedObject(store instanceStore, k key, factoryInfo factoryInfo, inv *invocation) (*reflect.Value, error) {
+	ck := factoryInfo.cacheKey(k)
+	inst := store.getInstance(ck)
+	if inst.isCreated() {
+		v := factoryInfo.pick(inst.values)
+		return &v, nil
+	}
+	// 同じ型の生成は同時に1つだけ行い、コンストラクタが1度だけ呼ばれるようにします。
+	// 別の goroutine の Invoke と生成中のインスタンスを互いに待ち合う場合は循環参照として扱います
+	if !inst.lock(inv) {
+		path := append(inv.path, k)
+		return nil, c.newResolveError(path, &CircularDependencyError{Path: c.newDependencies(path), lang: c.options.Language})
+	}
+	defer inst.unlock()
+	if inst.isCreated() {
+		v := factoryInfo.pick(inst.values)
 		return &v, nil
 	}
 	if !factoryInfo.isFunc {
-		c.cache[t] = factoryInfo.target
+		inst.set([]reflect.Value{factoryInfo.target})
 		return &factoryInfo.target, nil
 	}
-	lenIns := len(factoryInfo.ins)
-	args := make([]reflect.Value, lenIns)
-	for i, in := range factoryInfo.ins {
-		v, err := c.resolve(in, cache)
-		if err != nil {
-			return nil, err
-		}
-		args[i] = *v
+	owner := inv.owner
+	inv.owner = store
+	values, cleanup, err := c.construct(k, factoryInfo, inv)
+	inv.owner = owner
+	if err != nil {
+		return nil, err
 	}
+	inst.cleanup = cleanup
+	inst.set(values)
+	store.track(ck, inst)
+	v := factoryInfo.pick(values)
+	return &v, nil
+}
 
-	outs := factoryInfo.target.Call(args)
-	for _, out := range outs {
-		if err, ok := out.Interface().(error); ok {
-			return nil, err
-		}
+// getInstance は型に対応するキャッシュを取得し、存在しない場合は作成します
+func (c *container) getInstance(k key) *instance {
+	c.mu.RLock()
+	inst, ok := c.cache[k]
+	c.mu.RUnlock()
+	if ok {
+		return inst
 	}
-	if err := c.getError(outs); err != nil {
-		return nil, err
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if inst, ok := c.cache[k]; ok {
+		return inst
 	}
-	out := outs[0]
-	c.cache[t] = out
-	return &out, nil
+	inst = &instance{}
+	c.cache[k] = inst
+	return inst
 }
-func (c *container) resolveInvokeManagedObject(t reflect.Type, factoryInfo factoryInfo, cache *map[reflect.Type]reflect.Value) (*reflect.Value, error) {
-	cch := *cache
-	if v, ok := cch[t]; ok {
+func (c *container) resolveInvokeManagedObject(k key, factoryInfo factoryInfo, inv *invocation) (*reflect.Value, error) {
+	ck := factoryInfo.cacheKey(k)
+	if values, ok := inv.cache.get(ck); ok {
+		v := factoryInfo.pick(values)
 		return &v, nil
 	}
 	if !factoryInfo.isFunc {
-		cch[t] = factoryInfo.target
+		inv.cache.loadOrStore(ck, []reflect.Value{factoryInfo.target})
+		return &factoryInfo.target, nil
+	}
+	owner := inv.owner
+	inv.owner = nil
+	values, cleanup, err := c.construct(k, factoryInfo, inv)
+	inv.owner = owner
+	if err != nil {
+		return nil, err
+	}
+	if cleanup != nil {
+		inv.addCleanup(cleanup)
+	}
+	// Lazy から別の goroutine で同時に生成された場合は、先に記録されたインスタンスを返します
+	values = inv.cache.loadOrStore(ck, values)
+	v := factoryInfo.pick(values)
+	return &v, nil
+}
+
+// resolveTransientObject はキャッシュせずに毎回インスタンスを生成します。
+// ContainerManaged または ScopeManaged のインスタンスの依存関係として生成した場合、後処理はそのコンテナまたはスコープの Close で実行します
+func (c *container) resolveTransientObject(k key, factoryInfo factoryInfo, inv *invocation) (*reflect.Value, error) {
+	if !factoryInfo.isFunc {
 		return &factoryInfo.target, nil
 	}
-	lenIns := len(factoryInfo.ins)
-	args := make([]reflect.Value, lenIns)
-	for i, in := range factoryInfo.ins {
-		v, err := c.resolve(in, cache)
+	values, cleanup, err := c.construct(k, factoryInfo, inv)
+	if err != nil {
+		return nil, err
+	}
+	if cleanup != nil {
+		inv.addOwnedCleanup(cleanup)
+	}
+	v := factoryInfo.pick(values)
+	return &v, nil
+}
+
+// construct はコンストラクタの引数を解決して呼び出し、生成したインスタンスと後処理の関数を返します。
+// 複数の返り値を登録したコンストラクタの場合は全ての返り値を、そうでなければ先頭の返り値のみを返します
+func (c *container) construct(k key, factoryInfo factoryInfo, inv *invocation) ([]reflect.Value, func() error, error) {
+	if factoryInfo.base != nil {
+		base, err := c.resolveLifetime(k, *factoryInfo.base, inv)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		args[i] = *v
+		v := *base
+		if len(factoryInfo.interceptors) > 0 {
+			v = newProxy(k.t, v, factoryInfo.interceptors)
+		}
+		v, err = c.applyDecorators(k, v, factoryInfo.decorators, inv)
+		if err != nil {
+			return nil, nil, err
+		}
+		// 元のインスタンスが破棄されるため、デコレートしたインスタンスは破棄しません
+		return []reflect.Value{v}, func() error { return nil }, nil
+	}
+	if factoryInfo.structType != nil {
+		v, err := c.newStruct(factoryInfo.structType, factoryInfo.fields, inv)
+		if err != nil {
+			return nil, nil, err
+		}
+		return []reflect.Value{v}, nil, nil
+	}
+	args, err := c.resolveArgs(factoryInfo.ins, factoryInfo.isVariadic(), inv)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	outs := factoryInfo.target.Call(args)
+	outs, err := c.callRecover(k.t, factoryInfo.target, args)
+	if err != nil {
+		return nil, nil, c.newResolveError(inv.path, err)
+	}
 	if err := c.getError(outs); err != nil {
-		return nil, err
+		name, file, line := getFuncLocation(factoryInfo.target)
+		return nil, nil, c.newResolveError(inv.path, &ConstructorError{
+			Type:        k.t,
+			Constructor: name,
+			File:        file,
+			Line:        line,
+			Err:         err,
+			lang:        c.options.Language,
+		})
 	}
-	out := outs[0]
-	cch[t] = out
-	return &out, nil
+	var cleanup func() error
+	if factoryInfo.cleanupIndex > 0 {
+		cleanup = toCleanup(outs[factoryInfo.cleanupIndex])
+	}
+	if factoryInfo.producer == nil {
+		outs = outs[:1]
+	}
+	return outs, cleanup, nil
 }
 
-func (c *container) Verify() error {
-	lenIns := len(c.factoryInfos)
-	if lenIns == 0 {
-		return ErrNotFoundComponent
-	}
-	args := make([]reflect.Value, lenIns)
-	cache := make(map[reflect.Type]reflect.Value)
-	i := 0
-	for t := range c.factoryInfos {
-		v, err := c.resolve(t, &cache)
+// resolveArgs はコンストラクタや Invoke の引数を解決します。
+// variadic の場合、最後の引数はグループのメンバーに解決され、メンバーが存在しなければ空のスライスになります
+func (c *container) resolveArgs(ins []reflect.Type, variadic bool, inv *invocation) ([]reflect.Value, error) {
+	args := make([]reflect.Value, len(ins))
+	for i, in := range ins {
+		var v *reflect.Value
+		var err error
+		if variadic && i == len(ins)-1 {
+			v, err = c.resolveParam(param{key: key{t: in}, group: true}, inv)
+		} else {
+			v, err = c.resolveArg(in, inv)
+		}
 		if err != nil {
-			return err
+			return nil, err
 		}
 		args[i] = *v
-		i++
 	}
-	return nil
+	return args, nil
+}
+
+// callRecover は関数を呼び出し、panic した場合は ConstructorPanicError を返します。
+// t は関数が生成する型です。ContainerOptions.DisablePanicRecovery の場合は panic をそのまま伝播させます
+func (c *container) callRecover(t reflect.Type, fn reflect.Value, args []reflect.Value) (outs []reflect.Value, err error) {
+	if !c.options.DisablePanicRecovery {
+		defer func() {
+			if r := recover(); r != nil {
+				name, file, line := getFuncLocation(fn)
+				err = &ConstructorPanicError{
+					Type:        t,
+					Constructor: name,
+					File:        file,
+					Line:        line,
+					Value:       r,
+					Stack:       debug.Stack(),
+					lang:        c.options.Language,
+				}
+			}
+		}()
+	}
+	return callFunc(fn, args), nil
+}
+
+// Verify は登録された全てのコンポーネントが解決できることを検証します
+func (c *container) Verify(options ...VerifyOptions) error {
+	if len(options) > 1 {
+		return c.localize(ErrNoMultipleOption)
+	}
+	if len(options) == 1 && options[0].DryRun {
+		return c.verifyStatic()
+	}
+	// ScopeManaged なコンポーネントも解決できるように、検証用のスコープを開始します
+	s := newScope(c)
+	err := c.verify(newInvocation(WithScope(context.Background(), s)))
+	if cerr := s.Close(); cerr != nil && err == nil {
+		return cerr
+	}
+	return err
+}
+func (c *container) verify(inv *invocation) error {
+	c.mu.RLock()
+	registry := c.registry.clone()
+	c.mu.RUnlock()
+	if registry.isEmpty() {
+		return c.localize(ErrNotFoundComponent)
+	}
+	if errs := c.verifyLifetimes(registry); len(errs) > 0 {
+		return &VerifyError{Errors: errs, lang: c.options.Language}
+	}
+	keys := registry.keys()
+	for _, k := range keys {
+		if _, err := c.resolve(k, inv); err != nil {
+			return c.cleanup(inv, err)
+		}
+	}
+	return c.cleanup(inv, nil)
+}
+
+func (c *container) localize(err error) error {
+	return localize(c.options.Language, err)
+}
+
+// newDependencies は経路上の各型について、登録されたコンストラクタの情報を取得します
+func (c *container) newDependencies(path []key) []Dependency {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	deps := make([]Dependency, len(path))
+	for i, k := range path {
+		f, _ := c.registry.get(k)
+		deps[i] = newDependency(k, f)
+	}
+	return deps
+}
+
+func (c *container) newResolveError(path []key, err error) error {
+	last := path[len(path)-1]
+	return &ResolveError{Type: last.t, Name: last.name, Index: last.index, Path: c.newDependencies(path), Err: err, lang: c.options.Language}
+}
+
+func (c *container) newRegistrationError(target Target, err error) error {
+	return newRegistrationError(c, target, err)
 }
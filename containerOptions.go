@@ -2,5 +2,14 @@ package mydject
 
 type (
 	// ContainerOptions はコンテナの生成オプションです
-	ContainerOptions struct{}
+	ContainerOptions struct {
+		// Language はエラーメッセージの言語です。既定は Japanese です
+		Language Language
+		// CaptiveDependency はライフタイムスコープの長い登録が短い登録に依存している場合の扱いです。既定は CaptiveDependencyFailOnVerify です
+		CaptiveDependency CaptiveDependencyPolicy
+		// OnWarning は警告の通知先です。nil の場合は標準のロガーに出力します
+		OnWarning func(err error)
+		// DisablePanicRecovery の場合、コンストラクタや Invoke した関数の panic を ConstructorPanicError に変換せずにそのまま伝播させます
+		DisablePanicRecovery bool
+	}
 )
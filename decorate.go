@@ -0,0 +1,95 @@
+package mydject
+
+import "reflect"
+
+type (
+	// decorator は解決したインスタンスを包む関数です。ins は包まれるインスタンスを除いた依存関係です
+	decorator struct {
+		target reflect.Value
+		ins    []reflect.Type
+	}
+)
+
+// Decorate は func(inner T, deps...) T または func(inner T, deps...) (T, error) の形式の関数を T のデコレータとして登録します。
+// デコレータは名前付きの登録やグループのメンバーを含む全ての T の登録に、登録順に適用されます。
+// デコレートしたインスタンスは元の登録のライフタイムスコープに従ってキャッシュされます
+func (c *container) Decorate(target Target) (err error) {
+	t := reflect.TypeOf(target)
+	if t == nil || t.Kind() != reflect.Func {
+		return c.newRegistrationError(target, ErrRequireFunction)
+	}
+	if t.NumIn() < 1 || t.NumOut() < 1 || t.NumOut() > 2 || t.Out(0) != t.In(0) || (t.NumOut() == 2 && t.Out(1) != errorType) {
+		return c.newRegistrationError(target, ErrInvalidDecorator)
+	}
+	ins := getIns(t)[1:]
+	if _, err := getArgParams(ins, t.IsVariadic()); err != nil {
+		return c.newRegistrationError(target, err)
+	}
+	var warnings []error
+	defer func() {
+		c.warn(warnings...)
+	}()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	reg := newRegistration()
+	reg.decorated = t.In(0)
+	reg.decorator = decorator{target: reflect.ValueOf(target), ins: ins}
+	if c.options.CaptiveDependency != CaptiveDependencyFailOnVerify {
+		defer func() {
+			warnings, err = c.checkRegisteredLifetimes(target, reg, err)
+		}()
+	}
+	c.registry.decorate(reg.decorated, reg.decorator)
+	// 親コンテナから引き継いだデコレート済みのインスタンスは、追加したデコレータを含めて作り直します
+	for k := range c.cache {
+		if k.decorated && k.t == reg.decorated {
+			c.removeCache(reg, k)
+		}
+	}
+	return nil
+}
+
+// params はデコレータの元のインスタンス以外の引数の依存関係を返します
+func (d decorator) params() []param {
+	params, _ := getArgParams(d.ins, d.target.Type().IsVariadic())
+	return params
+}
+
+// decorate は登録をプロキシとデコレータを適用する登録に変換します。元の登録は同じライフタイムスコープで解決されます
+func (f factoryInfo) decorate(decorators []decorator) factoryInfo {
+	base := f
+	return factoryInfo{
+		isFunc:        true,
+		lifetimeScope: f.lifetimeScope,
+		base:          &base,
+		decorators:    decorators,
+		interceptors:  f.interceptors,
+	}
+}
+
+// applyDecorators はデコレータを登録順に適用します
+func (c *container) applyDecorators(k key, v reflect.Value, decorators []decorator, inv *invocation) (reflect.Value, error) {
+	for _, d := range decorators {
+		deps, err := c.resolveArgs(d.ins, d.target.Type().IsVariadic(), inv)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		outs, err := c.callRecover(k.t, d.target, append([]reflect.Value{v}, deps...))
+		if err != nil {
+			return reflect.Value{}, c.newResolveError(inv.path, err)
+		}
+		if err := c.getError(outs[1:]); err != nil {
+			name, file, line := getFuncLocation(d.target)
+			return reflect.Value{}, c.newResolveError(inv.path, &ConstructorError{
+				Type:        k.t,
+				Constructor: name,
+				File:        file,
+				Line:        line,
+				Err:         err,
+				lang:        c.options.Language,
+			})
+		}
+		v = outs[0]
+	}
+	return v, nil
+}
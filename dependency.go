@@ -0,0 +1,38 @@
+package mydject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type (
+	// Dependency は依存関係の経路上の1つの型と、その型を生成するコンストラクタの情報です
+	Dependency struct {
+		Type reflect.Type
+		Name string
+		// Index はグループのメンバーの1から始まる登録順です。グループのメンバーでない場合は 0 です
+		Index       int
+		Constructor string
+		File        string
+		Line        int
+	}
+)
+
+func newDependency(k key, factoryInfo factoryInfo) Dependency {
+	d := Dependency{Type: k.t, Name: k.name, Index: k.index}
+	if factoryInfo.isFunc {
+		d.Constructor, d.File, d.Line = getFuncLocation(factoryInfo.target)
+	}
+	return d
+}
+
+func (d Dependency) String() string {
+	if d.Constructor == "" {
+		return d.key().String()
+	}
+	return fmt.Sprintf("%v: %s (%s:%d)", d.key(), d.Constructor, d.File, d.Line)
+}
+
+func (d Dependency) key() key {
+	return key{t: d.Type, name: d.Name, index: d.Index}
+}
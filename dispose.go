@@ -0,0 +1,96 @@
+package mydject
+
+import (
+	"io"
+	"reflect"
+)
+
+type (
+	// createdInstance はコンテナまたはスコープが生成したインスタンスです
+	createdInstance struct {
+		key      key
+		instance *instance
+	}
+)
+
+// track はコンテナが生成したインスタンスを生成順に記録します
+func (c *container) track(k key, inst *instance) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.created = append(c.created, createdInstance{key: k, instance: inst})
+}
+
+// Close はこのコンテナが生成した ContainerManaged なインスタンスを生成の逆順に破棄します。
+// コンストラクタが後処理の関数を返した場合はそれを、そうでなければ io.Closer を実装したインスタンスの Close を呼び出し、
+// 失敗したものを全てまとめて返します。
+// 親コンテナから引き継いだインスタンスは破棄しません
+func (c *container) Close() error {
+	c.mu.Lock()
+	created := c.created
+	c.created = nil
+	for _, ci := range created {
+		// 破棄したインスタンスは次に解決されたときに再生成します
+		if c.cache[ci.key] == ci.instance {
+			delete(c.cache, ci.key)
+		}
+	}
+	c.mu.Unlock()
+	return disposeAll(created, c.options.Language)
+}
+
+// disposeAll はインスタンスを生成の逆順に破棄し、失敗したものを CloseError にまとめて返します
+func disposeAll(created []createdInstance, lang Language) error {
+	var errs []error
+	for i := len(created) - 1; i >= 0; i-- {
+		if err := dispose(created[i].instance, lang); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return &CloseError{Errors: errs, lang: lang}
+	}
+	return nil
+}
+
+func dispose(inst *instance, lang Language) error {
+	if inst.cleanup != nil {
+		return inst.cleanup()
+	}
+	values := disposables(inst.values)
+	var errs []error
+	for i := len(values) - 1; i >= 0; i-- {
+		v := values[i]
+		if !v.IsValid() || !v.CanInterface() {
+			continue
+		}
+		if closer, ok := v.Interface().(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	if len(errs) > 1 {
+		return &CloseError{Errors: errs, lang: lang}
+	}
+	return nil
+}
+
+// disposables は破棄の対象となる値を返します。Out を埋め込んだ構造体の返り値は、登録した各フィールドに展開します
+func disposables(values []reflect.Value) []reflect.Value {
+	var ds []reflect.Value
+	for _, v := range values {
+		if !v.IsValid() || !embeds(v.Type(), outType) {
+			ds = append(ds, v)
+			continue
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); !(field.Anonymous && field.Type == outType) {
+				ds = append(ds, v.Field(i))
+			}
+		}
+	}
+	return ds
+}
@@ -1,22 +1,312 @@
 package mydject
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 )
 
 var (
-	ErrNoMultipleOption                  = fmt.Errorf("オプションは単一である必要があります")
-	ErrNeedInterfaceOnPointerRegistering = fmt.Errorf("ポインタを登録する場合は、インターフェイスを指定する必要があります")
-	ErrRequireFunction                   = fmt.Errorf("関数を指定してください")
-	ErrNotFoundComponent                 = fmt.Errorf("解決するオブジェクトが存在しません")
-	ErrRequireResponse                   = fmt.Errorf("登録する関数には返り値が必要です")
+	ErrNoMultipleOption                  error = &sentinelError{msgNoMultipleOption}
+	ErrNeedInterfaceOnPointerRegistering error = &sentinelError{msgNeedInterfaceOnPointerRegistering}
+	ErrRequireFunction                   error = &sentinelError{msgRequireFunction}
+	ErrNotFoundComponent                 error = &sentinelError{msgNotFoundComponent}
+	ErrRequireResponse                   error = &sentinelError{msgRequireResponse}
+	ErrInvalidResolveComponent           error = &sentinelError{msgInvalidResolveComponent}
+	ErrNotAssignable                     error = &sentinelError{msgNotAssignable}
+	ErrRequirePointer                    error = &sentinelError{msgRequirePointer}
+	ErrRequireStruct                     error = &sentinelError{msgRequireStruct}
+	ErrInvalidInjectTag                  error = &sentinelError{msgInvalidInjectTag}
+	ErrUnexportedInjectField             error = &sentinelError{msgUnexportedInjectField}
+	ErrRequireScope                      error = &sentinelError{msgRequireScope}
+	ErrInvalidDecorator                  error = &sentinelError{msgInvalidDecorator}
+	ErrProxyNotFound                     error = &sentinelError{msgProxyNotFound}
+	ErrNilTarget                         error = &sentinelError{msgNilTarget}
+	ErrRequireInterface                  error = &sentinelError{msgRequireInterface}
+	ErrNotImplemented                    error = &sentinelError{msgNotImplemented}
 )
 
-func newErrInvalidResolveComponent(t reflect.Type) error {
-	return fmt.Errorf("指定されたタイプを解決できません。(%v)", t)
-}
+// IsErrInvalidResolveComponent は指定されたタイプを解決できなかったエラーかどうかを返します
 func IsErrInvalidResolveComponent(err error) bool {
-	return strings.HasPrefix(err.Error(), "指定されたタイプを解決できません。")
+	return errors.Is(err, ErrInvalidResolveComponent)
+}
+
+// sentinelError は errors.Is で比較するためのエラーです。メッセージは日本語です
+type sentinelError struct {
+	id messageID
+}
+
+func (e *sentinelError) Error() string {
+	return message(Japanese, e.id)
+}
+
+// localizedError は sentinelError のメッセージを指定された言語で返します
+type localizedError struct {
+	err  *sentinelError
+	lang Language
+}
+
+func (e *localizedError) Error() string {
+	return message(e.lang, e.err.id)
+}
+func (e *localizedError) Unwrap() error {
+	return e.err
+}
+
+// localize は sentinelError を指定された言語のエラーに変換します。日本語の場合はそのまま返します
+func localize(lang Language, err error) error {
+	s, ok := err.(*sentinelError)
+	if !ok || lang == Japanese {
+		return err
+	}
+	return &localizedError{err: s, lang: lang}
+}
+
+// ResolveError は型の解決に失敗した場合のエラーです。Path は最初に要求された型から Type までの経路です
+type ResolveError struct {
+	Type  reflect.Type
+	Name  string
+	Index int
+	Path  []Dependency
+	Err   error
+	lang  Language
+}
+
+func (e *ResolveError) Error() string {
+	return fmt.Sprintf(message(e.lang, msgResolveError), key{t: e.Type, name: e.Name, index: e.Index}, formatPath(e.Path), e.Err)
+}
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
+// ConstructorError はコンストラクタがエラーを返した場合のエラーです
+type ConstructorError struct {
+	Type        reflect.Type
+	Constructor string
+	File        string
+	Line        int
+	Err         error
+	lang        Language
+}
+
+func (e *ConstructorError) Error() string {
+	return fmt.Sprintf(message(e.lang, msgConstructorError), e.Constructor, e.File, e.Line, e.Err)
+}
+func (e *ConstructorError) Unwrap() error {
+	return e.Err
+}
+
+// ConstructorPanicError はコンストラクタ、デコレータまたは Invoke した関数が panic した場合のエラーです。
+// Type は生成しようとした型で、Invoke した関数の場合は関数の型です。Stack は panic した時点のスタックトレースです
+type ConstructorPanicError struct {
+	Type        reflect.Type
+	Constructor string
+	File        string
+	Line        int
+	Value       interface{}
+	Stack       []byte
+	lang        Language
+}
+
+func (e *ConstructorPanicError) Error() string {
+	return fmt.Sprintf(message(e.lang, msgConstructorPanicError), e.Constructor, e.File, e.Line, e.Value)
+}
+
+// Unwrap は panic の値が error の場合にそれを返します
+func (e *ConstructorPanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// RegistrationError は登録に失敗した場合のエラーです。Type は登録しようとした対象の型です
+type RegistrationError struct {
+	Type reflect.Type
+	Err  error
+	lang Language
+}
+
+func (e *RegistrationError) Error() string {
+	return fmt.Sprintf(message(e.lang, msgRegistrationError), e.Type, e.Err)
+}
+func (e *RegistrationError) Unwrap() error {
+	return e.Err
+}
+
+// InterfaceError は RegisterOptions.Interfaces の Interface に Type を登録できない場合のエラーです
+type InterfaceError struct {
+	Type      reflect.Type
+	Interface reflect.Type
+	Err       error
+	lang      Language
+}
+
+func (e *InterfaceError) Error() string {
+	return fmt.Sprintf(message(e.lang, msgInterfaceError), e.Type, e.Interface, e.Err)
+}
+func (e *InterfaceError) Unwrap() error {
+	return e.Err
+}
+
+// CircularDependencyError は循環参照を検出した場合のエラーです。Path の先頭と末尾は同じ型です
+type CircularDependencyError struct {
+	Path []Dependency
+	lang Language
+}
+
+func (e *CircularDependencyError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, message(e.lang, msgCircularDependencyError), formatPath(e.Path))
+	for _, d := range e.Path[:len(e.Path)-1] {
+		fmt.Fprintf(&b, "\n\t%v", d)
+	}
+	return b.String()
+}
+
+// LifetimeViolationError は長いライフタイムスコープのコンポーネントが短いライフタイムスコープのコンポーネントに依存している場合のエラーです
+type LifetimeViolationError struct {
+	Type                    reflect.Type
+	LifetimeScope           LifetimeScope
+	Dependency              reflect.Type
+	DependencyLifetimeScope LifetimeScope
+	lang                    Language
+}
+
+func (e *LifetimeViolationError) Error() string {
+	return fmt.Sprintf(message(e.lang, msgLifetimeViolationError), e.LifetimeScope, e.Type, e.DependencyLifetimeScope, e.Dependency)
+}
+
+// VerifyError は検証で検出された全てのエラーです
+type VerifyError struct {
+	Errors []error
+	lang   Language
+}
+
+func (e *VerifyError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf(message(e.lang, msgVerifyError), len(e.Errors), strings.Join(msgs, "\n"))
+}
+
+// Unwrap は検出された全てのエラーを返します
+func (e *VerifyError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is は errors.Is で Errors のいずれかが target に一致するかどうかを返します
+func (e *VerifyError) Is(target error) bool {
+	return isAny(e.Errors, target)
+}
+
+// As は errors.As で Errors のうち最初に target に代入できるエラーを設定します
+func (e *VerifyError) As(target interface{}) bool {
+	return asAny(e.Errors, target)
+}
+
+func formatPath(path []Dependency) string {
+	names := make([]string, len(path))
+	for i, d := range path {
+		names[i] = d.key().String()
+	}
+	return strings.Join(names, " -> ")
+}
+
+// CloseError はインスタンスの破棄で発生した全てのエラーです
+type CloseError struct {
+	Errors []error
+	lang   Language
+}
+
+func (e *CloseError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf(message(e.lang, msgCloseError), len(e.Errors), strings.Join(msgs, "\n"))
+}
+
+// Unwrap は発生した全てのエラーを返します
+func (e *CloseError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is は errors.Is で Errors のいずれかが target に一致するかどうかを返します
+func (e *CloseError) Is(target error) bool {
+	return isAny(e.Errors, target)
+}
+
+// As は errors.As で Errors のうち最初に target に代入できるエラーを設定します
+func (e *CloseError) As(target interface{}) bool {
+	return asAny(e.Errors, target)
+}
+
+// HookError は Hook がエラーを返したかタイムアウトした場合のエラーです。Event は OnStart または OnStop です
+type HookError struct {
+	Event string
+	Func  string
+	File  string
+	Line  int
+	Err   error
+	lang  Language
+}
+
+func (e *HookError) Error() string {
+	id := msgHookError
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		id = msgHookTimeoutError
+	}
+	return fmt.Sprintf(message(e.lang, id), e.Event, e.Func, e.File, e.Line, e.Err)
+}
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// StopError はアプリケーションの停止で発生した全てのエラーです
+type StopError struct {
+	Errors []error
+	lang   Language
+}
+
+func (e *StopError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf(message(e.lang, msgStopError), len(e.Errors), strings.Join(msgs, "\n"))
+}
+
+// Unwrap は発生した全てのエラーを返します
+func (e *StopError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is は errors.Is で Errors のいずれかが target に一致するかどうかを返します
+func (e *StopError) Is(target error) bool {
+	return isAny(e.Errors, target)
+}
+
+// As は errors.As で Errors のうち最初に target に代入できるエラーを設定します
+func (e *StopError) As(target interface{}) bool {
+	return asAny(e.Errors, target)
+}
+
+// isAny と asAny は Unwrap() []error を辿らない Go 1.20 より前の errors.Is と errors.As のために、複数のエラーを辿ります
+func isAny(errs []error, target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+func asAny(errs []error, target interface{}) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
 }
@@ -8,5 +8,58 @@ type (
 		ins           []reflect.Type
 		isFunc        bool
 		lifetimeScope LifetimeScope
+		// cleanupIndex はコンストラクタの返り値のうち後処理の関数の位置です。存在しない場合は 0 です
+		cleanupIndex int
+		// outIndex はこの登録で解決するコンストラクタの返り値の位置です
+		outIndex int
+		// outField は Out を埋め込んだ構造体の返り値の場合に、この登録で解決するフィールドの位置です
+		outField []int
+		// producer は複数の返り値を登録したコンストラクタの場合に、全ての登録で共有されます
+		producer *producer
+		// fields は構造体を登録した場合に注入するフィールドです
+		fields []param
+		// structType は構造体を登録した場合の生成する型です
+		structType reflect.Type
+		// base はデコレータを適用する場合の元の登録です
+		base *factoryInfo
+		// decorators は base に登録順に適用するデコレータです
+		decorators []decorator
+		// interceptors はプロキシで包む場合にメソッドの呼び出しに適用するインターセプタです
+		interceptors []Interceptor
+	}
+	// producer は複数の返り値を登録したコンストラクタです。1回の呼び出しの返り値を全ての登録で共有するためのキャッシュのキーになります
+	producer struct {
+		target reflect.Value
 	}
 )
+
+// cacheKey はインスタンスをキャッシュするキーを返します
+func (f factoryInfo) cacheKey(k key) key {
+	if f.base != nil {
+		return k.decoratedKey()
+	}
+	if f.producer == nil {
+		return k
+	}
+	return key{producer: f.producer}
+}
+
+// params はコンストラクタの引数と注入するフィールドの依存関係を返します
+func (f factoryInfo) params() []param {
+	params, _ := getArgParams(f.ins, f.isVariadic())
+	return append(params, f.fields...)
+}
+
+// isVariadic は可変長引数のコンストラクタかどうかを返します
+func (f factoryInfo) isVariadic() bool {
+	return f.target.Kind() == reflect.Func && f.target.Type().IsVariadic()
+}
+
+// pick はコンストラクタの返り値からこの登録で解決するインスタンスを取り出します
+func (f factoryInfo) pick(values []reflect.Value) reflect.Value {
+	v := values[f.outIndex]
+	if f.outField != nil {
+		return v.FieldByIndex(f.outField)
+	}
+	return v
+}
@@ -0,0 +1,88 @@
+package mydject
+
+import "reflect"
+
+// As は型 I を返します。RegisterOptions.Interfaces の指定に使います
+//
+//	container.Register(NewService3(), mydject.RegisterOptions{Interfaces: []reflect.Type{mydject.As[Service3]()}})
+func As[I any]() reflect.Type {
+	return reflect.TypeOf((*I)(nil)).Elem()
+}
+
+// Resolve はコンテナから T を解決します
+func Resolve[T any](c ServiceLocator) (T, error) {
+	var result T
+	err := c.Invoke(func(v T) {
+		result = v
+	})
+	return result, err
+}
+
+// InvokeR は func(deps...) R または func(deps...) (R, error) の形式の invoker を呼び出し、返り値を返します
+func InvokeR[R any](c ServiceLocator, invoker Invoker) (R, error) {
+	var result R
+	t := reflect.TypeOf(invoker)
+	if t != nil && t.Kind() == reflect.Func && (t.NumOut() == 0 || !t.Out(0).AssignableTo(As[R]())) {
+		return result, localize(languageOf(c), ErrNotAssignable)
+	}
+	outs, err := c.Call(invoker)
+	if len(outs) > 0 {
+		reflect.ValueOf(&result).Elem().Set(outs[0])
+	}
+	return result, err
+}
+
+// MustResolve はコンテナから T を解決します。解決できない場合は panic します
+func MustResolve[T any](c ServiceLocator) T {
+	v, err := Resolve[T](c)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// ResolveNamed はコンテナから名前を指定して T を解決します
+func ResolveNamed[T any](c ServiceLocator, name string) (T, error) {
+	var result T
+	err := c.ResolveNamed(name, &result)
+	return result, err
+}
+
+// Provide はコンストラクタを T として登録します。T がインターフェイスの場合は RegisterOptions.Interfaces に T が追加されます。
+// オプションを指定しない場合のライフタイムスコープは Register と同じく InvokeManaged です
+func Provide[T any](c Container, constructor Target, options ...RegisterOptions) error {
+	if len(options) > 1 {
+		return newRegistrationError(c, constructor, ErrNoMultipleOption)
+	}
+	if isNilTarget(constructor) {
+		return newRegistrationError(c, constructor, ErrNilTarget)
+	}
+	out, _, err := getTargetReflectionInfos(constructor)
+	if err != nil {
+		return newRegistrationError(c, constructor, err)
+	}
+	t := As[T]()
+	if out == t {
+		return c.Register(constructor, options...)
+	}
+	if t.Kind() != reflect.Interface || !out.AssignableTo(t) {
+		return newRegistrationError(c, constructor, ErrNotAssignable)
+	}
+	// オプションが指定されていない場合は Register と同じく InvokeManaged で登録します
+	option := RegisterOptions{LifetimeScope: InvokeManaged}
+	if len(options) == 1 {
+		option = options[0]
+	}
+	option.Interfaces = append(append([]reflect.Type{}, option.Interfaces...), t)
+	return c.Register(constructor, option)
+}
+
+// ProvideValue は値を T として ContainerManaged で登録します
+func ProvideValue[T any](c Container, value T, options ...RegisterOptions) error {
+	return Provide[T](c, value, options...)
+}
+
+func newRegistrationError(c Container, target Target, err error) error {
+	lang := languageOf(c)
+	return &RegistrationError{Type: reflect.TypeOf(target), Err: localize(lang, err), lang: lang}
+}
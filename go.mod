@@ -2,4 +2,4 @@ module github.com/ohishikaito/mydject
 
 go 1.19
 
-require github.com/google/uuid v1.3.0 // indirect
+require github.com/google/uuid v1.3.0
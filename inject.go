@@ -0,0 +1,155 @@
+package mydject
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+const injectTagName = "inject"
+
+type (
+	// param は解決する依存関係です。構造体のフィールドの場合は index にフィールドの位置を持ちます
+	param struct {
+		key      key
+		optional bool
+		// group の場合、key の型はスライスで、グループのメンバーを解決します。メンバーが存在しない場合は空のスライスになります
+		group bool
+		index int
+	}
+	// injectTag は inject タグの解析結果です
+	injectTag struct {
+		name     string
+		optional bool
+		group    bool
+	}
+	// structTarget はフィールドに依存関係を注入して生成する構造体の登録です
+	structTarget struct {
+		t reflect.Type
+	}
+)
+
+// Struct は T を inject タグの付いたフィールドに依存関係を注入して生成するように登録するための Target です。
+// T は構造体または構造体のポインタです
+//
+//	container.Register(mydject.Struct[Handler]())
+func Struct[T any]() Target {
+	return structTarget{t: As[T]()}
+}
+
+// structType は t が構造体または構造体のポインタの場合に構造体の型を返します
+func structType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t, t.Kind() == reflect.Struct
+}
+
+// parseInjectTag は inject タグを解析します。タグは name=<名前>、optional、group をカンマ区切りで指定します
+func parseInjectTag(tag string) (injectTag, error) {
+	var result injectTag
+	if tag == "" {
+		return result, nil
+	}
+	for _, opt := range strings.Split(tag, ",") {
+		opt = strings.TrimSpace(opt)
+		switch {
+		case opt == "optional":
+			result.optional = true
+		case opt == "group":
+			result.group = true
+		case strings.HasPrefix(opt, "name="):
+			result.name = strings.TrimPrefix(opt, "name=")
+		default:
+			return injectTag{}, ErrInvalidInjectTag
+		}
+	}
+	return result, nil
+}
+
+// newFieldParam は構造体のフィールドの依存関係を inject タグから生成します
+func newFieldParam(field reflect.StructField, index int) (param, error) {
+	tag, err := parseInjectTag(field.Tag.Get(injectTagName))
+	if err != nil {
+		return param{}, err
+	}
+	if tag.group && field.Type.Kind() != reflect.Slice {
+		return param{}, ErrInvalidInjectTag
+	}
+	return param{key: key{t: field.Type, name: tag.name}, optional: tag.optional, group: tag.group, index: index}, nil
+}
+
+// getInjectFields は inject タグの付いたフィールドを返します
+func getInjectFields(t reflect.Type) ([]param, error) {
+	fields := []param{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if _, ok := field.Tag.Lookup(injectTagName); !ok {
+			continue
+		}
+		if !field.IsExported() {
+			return nil, ErrUnexportedInjectField
+		}
+		p, err := newFieldParam(field, i)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, p)
+	}
+	return fields, nil
+}
+
+// InjectInto は target が指す構造体の inject タグの付いたフィールドに依存関係を注入します
+func (c *container) InjectInto(target interface{}) error {
+	return c.injectInto(target, newInvocation(context.Background()))
+}
+func (c *container) injectInto(target interface{}, inv *invocation) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return c.localize(ErrRequireStruct)
+	}
+	fields, err := getInjectFields(v.Elem().Type())
+	if err != nil {
+		return c.localize(err)
+	}
+	return c.cleanup(inv, c.injectFields(v.Elem(), fields, inv))
+}
+
+// newStruct は構造体を生成してフィールドに依存関係を注入します。t が構造体のポインタの場合はポインタを返します
+func (c *container) newStruct(t reflect.Type, fields []param, inv *invocation) (reflect.Value, error) {
+	st, _ := structType(t)
+	v := reflect.New(st)
+	if err := c.injectFields(v.Elem(), fields, inv); err != nil {
+		return reflect.Value{}, err
+	}
+	if t.Kind() == reflect.Ptr {
+		return v, nil
+	}
+	return v.Elem(), nil
+}
+
+func (c *container) injectFields(v reflect.Value, fields []param, inv *invocation) error {
+	for _, field := range fields {
+		resolved, err := c.resolveParam(field, inv)
+		if err != nil {
+			return err
+		}
+		v.Field(field.index).Set(*resolved)
+	}
+	return nil
+}
+
+// resolveParam は依存関係を解決します。optional で登録が存在しない場合はゼロ値を返します
+func (c *container) resolveParam(p param, inv *invocation) (*reflect.Value, error) {
+	if p.group {
+		c.mu.RLock()
+		members, _ := c.registry.members(p.key)
+		c.mu.RUnlock()
+		return c.resolveGroup(p.key, members, inv)
+	}
+	if p.optional && !c.isRegistered(p.key) {
+		v := reflect.Zero(p.key.t)
+		return &v, nil
+	}
+	return c.resolve(p.key, inv)
+}
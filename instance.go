@@ -0,0 +1,72 @@
+package mydject
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+type (
+	// instance は ContainerManaged なオブジェクトのキャッシュです
+	instance struct {
+		mu sync.Mutex
+		// values は生成したインスタンスです。複数の返り値を登録したコンストラクタの場合は全ての返り値を保持します
+		values []reflect.Value
+		// created は values の設定後に true になります。mu を待たずに生成済みかどうかを確認できます
+		created atomic.Bool
+		// cleanup はコンストラクタが返した後処理の関数です
+		cleanup func() error
+		// builder は mu をロックしてインスタンスを生成している Invoke です。buildMu で保護します
+		builder *invocation
+	}
+)
+
+// buildMu は instance.builder と invocation.waiting を保護します。
+// 生成中のインスタンスを互いに待ち合う Invoke を検出するために、全てのコンテナとスコープで共有します
+var buildMu sync.Mutex
+
+func (i *instance) isCreated() bool {
+	return i.created.Load()
+}
+
+// lock は他の Invoke による生成の完了を待って mu をロックし、inv を生成中の Invoke として記録します。
+// 待っている Invoke を辿って inv に戻る場合は、互いに待ち合って終わらないため、ロックせずに false を返します
+func (i *instance) lock(inv *invocation) bool {
+	buildMu.Lock()
+	for b := i.builder; b != nil; b = b.waiting.builder {
+		if b == inv {
+			buildMu.Unlock()
+			return false
+		}
+		if b.waiting == nil {
+			break
+		}
+	}
+	inv.waiting = i
+	buildMu.Unlock()
+
+	i.mu.Lock()
+	buildMu.Lock()
+	inv.waiting = nil
+	i.builder = inv
+	buildMu.Unlock()
+	return true
+}
+func (i *instance) unlock() {
+	buildMu.Lock()
+	i.builder = nil
+	buildMu.Unlock()
+	i.mu.Unlock()
+}
+func (i *instance) set(values []reflect.Value) {
+	i.values = values
+	i.created.Store(true)
+}
+
+// instanceStore はキャッシュしたインスタンスを保持するコンテナまたはスコープです
+type instanceStore interface {
+	// getInstance は型に対応するキャッシュを取得し、存在しない場合は作成します
+	getInstance(k key) *instance
+	// track は生成したインスタンスを生成順に記録します
+	track(k key, inst *instance)
+}
@@ -0,0 +1,145 @@
+package mydject
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+type (
+	// invocation は1回の Invoke における解決の状態です
+	invocation struct {
+		cache *invocationCache
+		// ctx は context.Context を要求したコンストラクタに渡されます。キャンセルされると解決を中止します
+		ctx context.Context
+		// scope は ctx が保持する ScopeManaged なインスタンスのスコープです。スコープの外では nil です
+		scope *scope
+		path  []key
+		// cleanups は InvokeManaged なインスタンスの後処理を生成順に保持します
+		cleanups []func() error
+		// mu は Lazy や Provider から別の goroutine で参照される path と cleanups を保護します
+		mu sync.Mutex
+		// finished は Invoke が終了し、後処理を実行した後に true になります
+		finished bool
+		// owner は ContainerManaged または ScopeManaged のインスタンスを生成している間、それをキャッシュするコンテナまたはスコープです。
+		// その依存関係として生成した Transient なインスタンスの後処理は、Invoke の終了時ではなく owner の Close で実行します
+		owner instanceStore
+		// waiting は他の Invoke が生成中のため、生成の完了を待っているインスタンスです。buildMu で保護します
+		waiting *instance
+	}
+	// invocationCache は InvokeManaged なインスタンスのキャッシュです。Lazy から別の goroutine で解決される場合に備えて mu で保護します
+	invocationCache struct {
+		mu     sync.Mutex
+		values map[key][]reflect.Value
+	}
+)
+
+func newInvocation(ctx context.Context) *invocation {
+	s, _ := ScopeFromContext(ctx).(*scope)
+	return &invocation{cache: &invocationCache{values: make(map[key][]reflect.Value)}, ctx: ctx, scope: s}
+}
+
+// fresh は inv と同じ ctx とスコープで新しい Invoke の状態を返します。
+// inv の Invoke が既に終了している場合は、キャンセルされない context を使います
+func (inv *invocation) fresh() *invocation {
+	if !inv.isFinished() {
+		return newInvocation(inv.ctx)
+	}
+	ctx := context.Background()
+	if inv.scope != nil {
+		ctx = WithScope(ctx, inv.scope)
+	}
+	return newInvocation(ctx)
+}
+
+// fork は inv とキャッシュを共有し、解決途中の経路を引き継いだ invocation を返します。
+// 経路は複製するため、返した invocation は別の goroutine で解決できます
+func (inv *invocation) fork() *invocation {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	return &invocation{cache: inv.cache, ctx: inv.ctx, scope: inv.scope, path: append([]key{}, inv.path...)}
+}
+
+func (c *invocationCache) get(k key) ([]reflect.Value, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	values, ok := c.values[k]
+	return values, ok
+}
+
+// loadOrStore は k のキャッシュが存在すればそれを、そうでなければ values を記録して返します
+func (c *invocationCache) loadOrStore(k key, values []reflect.Value) []reflect.Value {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.values[k]; ok {
+		return cached
+	}
+	c.values[k] = values
+	return values
+}
+
+// isResolving は指定されたコンポーネントが解決途中かどうかを返します
+func (inv *invocation) isResolving(k key) bool {
+	for _, p := range inv.path {
+		if p == k {
+			return true
+		}
+	}
+	return false
+}
+func (inv *invocation) push(k key) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.path = append(inv.path, k)
+}
+func (inv *invocation) pop() {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.path = inv.path[:len(inv.path)-1]
+}
+
+// addCleanup は後処理を追加します。Invoke が終了している場合は追加せずに false を返します
+func (inv *invocation) addCleanup(cleanups ...func() error) bool {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	if inv.finished {
+		return false
+	}
+	inv.cleanups = append(inv.cleanups, cleanups...)
+	return true
+}
+
+// takeCleanups は後処理を取り出します。取り出した後処理は再度実行されません
+func (inv *invocation) takeCleanups() []func() error {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	cleanups := inv.cleanups
+	inv.cleanups = nil
+	return cleanups
+}
+
+// addOwnedCleanup は Transient なインスタンスの後処理を、owner が存在すればそこに、そうでなければ Invoke の後処理に追加します
+func (inv *invocation) addOwnedCleanup(cleanup func() error) {
+	if inv.owner != nil {
+		inv.owner.track(key{}, &instance{cleanup: cleanup})
+		return
+	}
+	inv.addCleanup(cleanup)
+}
+
+// finish は Invoke を終了し、後処理を取り出します。以降の addCleanup は失敗します
+func (inv *invocation) finish() []func() error {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.finished = true
+	cleanups := inv.cleanups
+	inv.cleanups = nil
+	return cleanups
+}
+
+// isFinished は Invoke が終了しているかどうかを返します
+func (inv *invocation) isFinished() bool {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	return inv.finished
+}
@@ -0,0 +1,45 @@
+package mydject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type (
+	// key は登録されたコンポーネントを識別する型と名前の組です。名前のない登録は name が空です
+	key struct {
+		t    reflect.Type
+		name string
+		// index はグループのメンバーの1から始まる登録順です。グループのメンバーでない場合は 0 です
+		index int
+		// producer は複数の返り値を登録したコンストラクタのキャッシュのキーの場合のみ設定されます
+		producer *producer
+		// decorated はデコレートしたインスタンスのキャッシュのキーの場合のみ true です
+		decorated bool
+	}
+)
+
+func (k key) member(index int) key {
+	return key{t: k.t, name: k.name, index: index}
+}
+func (k key) group() key {
+	return key{t: k.t, name: k.name}
+}
+func (k key) decoratedKey() key {
+	return key{t: k.t, name: k.name, index: k.index, decorated: true}
+}
+
+func (k key) String() string {
+	if k.producer != nil {
+		name, _, _ := getFuncLocation(k.producer.target)
+		return name
+	}
+	s := k.t.String()
+	if k.name != "" {
+		s = fmt.Sprintf("%s(%s)", s, k.name)
+	}
+	if k.index != 0 {
+		s = fmt.Sprintf("%s[%d]", s, k.index)
+	}
+	return s
+}
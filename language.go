@@ -0,0 +1,11 @@
+package mydject
+
+// Language はエラーメッセージの言語です
+type Language int
+
+const (
+	// Japanese は日本語のエラーメッセージです
+	Japanese Language = iota
+	// English は英語のエラーメッセージです
+	English
+)
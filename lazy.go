@@ -0,0 +1,171 @@
+package mydject
+
+import (
+	"reflect"
+	"sync"
+)
+
+type (
+	// Lazy は最初に Get を呼び出したときに T を解決する依存関係です。
+	// 解決したインスタンスは T のライフタイムスコープに従ってキャッシュされます。
+	// Lazy が解決された Invoke の中では InvokeManaged な T は Invoke の引数と同じインスタンスになり、
+	// Invoke の終了後は新しい Invoke の状態で解決します。複数の goroutine から Get を呼び出せます
+	Lazy[T any] struct {
+		state *lazyState[T]
+	}
+	lazyState[T any] struct {
+		mu    sync.Mutex
+		get   func() (*reflect.Value, error)
+		value T
+		done  bool
+	}
+	// Provider は Get を呼び出すたびに T を解決する依存関係です。
+	// InvokeManaged な T は呼び出しごとに新しいインスタンスが生成され、後処理は Provider が解決された Invoke の終了時に実行されます。
+	// Invoke の終了後に呼び出した場合の後処理は、スコープまたはコンテナの Close で実行されます
+	Provider[T any] struct {
+		get func() (*reflect.Value, error)
+	}
+	// providerFunc は func() (T, error) の依存関係です。Provider と同じく呼び出しごとに T を解決します
+	providerFunc struct {
+		t reflect.Type
+	}
+)
+
+// Get は T を解決します。一度解決に成功した後は同じインスタンスを返します
+func (l Lazy[T]) Get() (T, error) {
+	var zero T
+	if l.state == nil {
+		return zero, ErrNotFoundComponent
+	}
+	l.state.mu.Lock()
+	defer l.state.mu.Unlock()
+	if l.state.done {
+		return l.state.value, nil
+	}
+	v, err := l.state.get()
+	if err != nil {
+		return zero, err
+	}
+	reflect.ValueOf(&l.state.value).Elem().Set(*v)
+	l.state.done = true
+	return l.state.value, nil
+}
+
+func (Lazy[T]) wrappedKey(k key) key {
+	return key{t: As[T](), name: k.name}
+}
+func (Lazy[T]) isOptional() bool {
+	return false
+}
+func (Lazy[T]) isDeferred() bool {
+	return true
+}
+func (l Lazy[T]) resolveWrapper(c *container, k key, inv *invocation) (reflect.Value, error) {
+	inner := l.wrappedKey(k)
+	// Lazy を保持するインスタンスと同じく、Transient な T の後処理はそのコンテナまたはスコープで実行します
+	owner := inv.owner
+	get := func() (*reflect.Value, error) {
+		return c.resolveLazy(inner, inv, owner)
+	}
+	return reflect.ValueOf(Lazy[T]{state: &lazyState[T]{get: get}}), nil
+}
+
+// Get は T を解決します
+func (p Provider[T]) Get() (T, error) {
+	var result T
+	if p.get == nil {
+		return result, ErrNotFoundComponent
+	}
+	v, err := p.get()
+	if err != nil {
+		return result, err
+	}
+	reflect.ValueOf(&result).Elem().Set(*v)
+	return result, nil
+}
+
+func (Provider[T]) wrappedKey(k key) key {
+	return key{t: As[T](), name: k.name}
+}
+func (Provider[T]) isOptional() bool {
+	return false
+}
+func (Provider[T]) isDeferred() bool {
+	return true
+}
+func (p Provider[T]) resolveWrapper(c *container, k key, inv *invocation) (reflect.Value, error) {
+	inner := p.wrappedKey(k)
+	get := func() (*reflect.Value, error) {
+		return c.resolveFresh(inner, inv)
+	}
+	return reflect.ValueOf(Provider[T]{get: get}), nil
+}
+
+// asProviderFunc は t が func() (T, error) の場合に wrapper を返します
+func asProviderFunc(t reflect.Type) (wrapper, bool) {
+	if t.Kind() != reflect.Func || t.NumIn() != 0 || t.NumOut() != 2 || t.Out(1) != errorType {
+		return nil, false
+	}
+	return providerFunc{t: t}, true
+}
+func (p providerFunc) wrappedKey(k key) key {
+	return key{t: p.t.Out(0), name: k.name}
+}
+func (providerFunc) isOptional() bool {
+	return false
+}
+func (providerFunc) isDeferred() bool {
+	return true
+}
+func (p providerFunc) resolveWrapper(c *container, k key, inv *invocation) (reflect.Value, error) {
+	inner := p.wrappedKey(k)
+	fn := reflect.MakeFunc(p.t, func([]reflect.Value) []reflect.Value {
+		result := reflect.New(inner.t).Elem()
+		err := reflect.New(errorType).Elem()
+		v, rerr := c.resolveFresh(inner, inv)
+		if rerr != nil {
+			err.Set(reflect.ValueOf(rerr))
+		} else {
+			result.Set(*v)
+		}
+		return []reflect.Value{result, err}
+	})
+	return fn, nil
+}
+
+// resolveFresh は新しい Invoke の状態で解決します。後処理は parent の Invoke の終了時に実行されます。
+// parent の Invoke が既に終了している場合は、キャンセルされない context で解決し、後処理はスコープまたはコンテナの Close で実行します
+func (c *container) resolveFresh(k key, parent *invocation) (*reflect.Value, error) {
+	return c.resolveChild(k, parent, parent.fresh())
+}
+
+// resolveLazy は Lazy の T を解決します。parent の Invoke の中ではキャッシュと解決途中の経路を引き継ぐため、
+// InvokeManaged な T は Invoke の引数と同じインスタンスになり、コンストラクタの中で Get を呼び出した場合の循環参照を検出します。
+// parent の Invoke が既に終了している場合は resolveFresh と同じく新しい Invoke の状態で解決します。
+// owner は Lazy を保持する ContainerManaged または ScopeManaged のインスタンスのコンテナまたはスコープです
+func (c *container) resolveLazy(k key, parent *invocation, owner instanceStore) (*reflect.Value, error) {
+	var inv *invocation
+	if parent.isFinished() {
+		inv = parent.fresh()
+	} else {
+		inv = parent.fork()
+	}
+	inv.owner = owner
+	return c.resolveChild(k, parent, inv)
+}
+
+// resolveChild は inv で解決し、後処理を parent の Invoke に追加します。
+// parent の Invoke が既に終了している場合は、後処理をスコープまたはコンテナに記録し Close で実行します
+func (c *container) resolveChild(k key, parent *invocation, inv *invocation) (*reflect.Value, error) {
+	v, err := c.resolve(k, inv)
+	if cleanups := inv.takeCleanups(); !parent.addCleanup(cleanups...) {
+		var store instanceStore = c
+		if parent.scope != nil {
+			store = parent.scope
+		}
+		for _, cleanup := range cleanups {
+			store.track(key{}, &instance{cleanup: cleanup})
+		}
+	}
+	return v, err
+}
@@ -1,5 +1,7 @@
 package mydject
 
+import "fmt"
+
 // LifetimeScope はインスタンスのライフタイムスコープです
 type LifetimeScope int
 
@@ -8,4 +10,23 @@ const (
 	ContainerManaged LifetimeScope = iota
 	// InvokeManaged の場合、その呼び出し内でインスタンスは一意です
 	InvokeManaged
+	// ScopeManaged の場合、BeginScope で開始したスコープ内でインスタンスは一意です。スコープの外では解決できません
+	ScopeManaged
+	// Transient の場合、依存関係として要求されるたびに新しいインスタンスを生成します。後処理は Invoke の終了時に実行されます。
+	// ContainerManaged または ScopeManaged のインスタンスの依存関係として生成された場合は、要求した側と同じくコンテナまたはスコープの Close で実行されます
+	Transient
 )
+
+func (s LifetimeScope) String() string {
+	switch s {
+	case ContainerManaged:
+		return "ContainerManaged"
+	case InvokeManaged:
+		return "InvokeManaged"
+	case ScopeManaged:
+		return "ScopeManaged"
+	case Transient:
+		return "Transient"
+	}
+	return fmt.Sprintf("LifetimeScope(%d)", int(s))
+}
@@ -0,0 +1,107 @@
+package mydject
+
+type messageID int
+
+const (
+	msgNoMultipleOption messageID = iota
+	msgNeedInterfaceOnPointerRegistering
+	msgRequireFunction
+	msgNotFoundComponent
+	msgRequireResponse
+	msgInvalidResolveComponent
+	msgNotAssignable
+	msgRequirePointer
+	msgRequireStruct
+	msgInvalidInjectTag
+	msgUnexportedInjectField
+	msgRequireScope
+	msgInvalidDecorator
+	msgProxyNotFound
+	msgNilTarget
+	msgRequireInterface
+	msgNotImplemented
+	msgResolveError
+	msgConstructorError
+	msgConstructorPanicError
+	msgRegistrationError
+	msgInterfaceError
+	msgCircularDependencyError
+	msgLifetimeViolationError
+	msgVerifyError
+	msgCloseError
+	msgHookError
+	msgHookTimeoutError
+	msgStopError
+)
+
+var messages = map[Language]map[messageID]string{
+	Japanese: {
+		msgNoMultipleOption:                  "オプションは単一である必要があります",
+		msgNeedInterfaceOnPointerRegistering: "ポインタを登録する場合は、インターフェイスを指定する必要があります",
+		msgRequireFunction:                   "関数を指定してください",
+		msgNotFoundComponent:                 "解決するオブジェクトが存在しません",
+		msgRequireResponse:                   "登録する関数には返り値が必要です",
+		msgInvalidResolveComponent:           "指定されたタイプを解決できません。",
+		msgNotAssignable:                     "登録する値の型を指定された型に代入できません",
+		msgRequirePointer:                    "nil でないポインタを指定してください",
+		msgRequireStruct:                     "構造体または構造体のポインタを指定してください",
+		msgInvalidInjectTag:                  "inject タグが不正です",
+		msgUnexportedInjectField:             "inject タグは公開されたフィールドに指定してください",
+		msgRequireScope:                      "ScopeManaged なコンポーネントはスコープの中で解決してください",
+		msgInvalidDecorator:                  "デコレータは func(inner T, ...) T または func(inner T, ...) (T, error) の形式で指定してください",
+		msgProxyNotFound:                     "インターセプタを指定する場合は、mydject-proxy でプロキシを生成したインターフェイスを Interfaces に指定する必要があります",
+		msgNilTarget:                         "nil は登録できません",
+		msgRequireInterface:                  "Interfaces にはインターフェイスの型を指定してください",
+		msgNotImplemented:                    "登録する型がインターフェイスを実装していません",
+		msgResolveError:                      "%v の解決に失敗しました。(%s): %v",
+		msgConstructorError:                  "コンストラクタ %s (%s:%d) がエラーを返しました: %v",
+		msgConstructorPanicError:             "%s (%s:%d) が panic しました: %v",
+		msgRegistrationError:                 "%v を登録できません: %v",
+		msgInterfaceError:                    "%v を %v として登録できません: %v",
+		msgCircularDependencyError:           "循環参照を検出しました。(%s)",
+		msgLifetimeViolationError:            "ライフタイムスコープが %v の %v が、ライフタイムスコープが %v の %v に依存しています",
+		msgVerifyError:                       "検証で %d 件のエラーが検出されました。\n%s",
+		msgCloseError:                        "%d 件のインスタンスの破棄に失敗しました。\n%s",
+		msgHookError:                         "%s フック %s (%s:%d) がエラーを返しました: %v",
+		msgHookTimeoutError:                  "%s フック %s (%s:%d) がタイムアウトしました: %v",
+		msgStopError:                         "アプリケーションの停止で %d 件のエラーが発生しました。\n%s",
+	},
+	English: {
+		msgNoMultipleOption:                  "only a single option can be specified",
+		msgNeedInterfaceOnPointerRegistering: "interfaces must be specified when registering a pointer",
+		msgRequireFunction:                   "a function must be specified",
+		msgNotFoundComponent:                 "no component to resolve",
+		msgRequireResponse:                   "a function to register must have a return value",
+		msgInvalidResolveComponent:           "the specified type cannot be resolved",
+		msgNotAssignable:                     "the registered type is not assignable to the specified type",
+		msgRequirePointer:                    "a non-nil pointer must be specified",
+		msgRequireStruct:                     "a struct or a pointer to a struct must be specified",
+		msgInvalidInjectTag:                  "the inject tag is invalid",
+		msgUnexportedInjectField:             "the inject tag must be specified on an exported field",
+		msgRequireScope:                      "a ScopeManaged component must be resolved within a scope",
+		msgInvalidDecorator:                  "a decorator must be func(inner T, ...) T or func(inner T, ...) (T, error)",
+		msgProxyNotFound:                     "interceptors require interfaces whose proxies are generated by mydject-proxy in Interfaces",
+		msgNilTarget:                         "nil cannot be registered",
+		msgRequireInterface:                  "Interfaces must contain interface types",
+		msgNotImplemented:                    "the registered type does not implement the interface",
+		msgResolveError:                      "failed to resolve %v (%s): %v",
+		msgConstructorError:                  "constructor %s (%s:%d) returned an error: %v",
+		msgConstructorPanicError:             "%s (%s:%d) panicked: %v",
+		msgRegistrationError:                 "cannot register %v: %v",
+		msgInterfaceError:                    "cannot register %v as %v: %v",
+		msgCircularDependencyError:           "detected circular dependency (%s)",
+		msgLifetimeViolationError:            "%[2]v with lifetime scope %[1]v depends on %[4]v with lifetime scope %[3]v",
+		msgVerifyError:                       "verification found %d errors\n%s",
+		msgCloseError:                        "failed to dispose %d instances\n%s",
+		msgHookError:                         "%s hook %s (%s:%d) returned an error: %v",
+		msgHookTimeoutError:                  "%s hook %s (%s:%d) timed out: %v",
+		msgStopError:                         "stopping the application failed with %d errors\n%s",
+	},
+}
+
+func message(lang Language, id messageID) string {
+	if m, ok := messages[lang][id]; ok {
+		return m
+	}
+	return messages[Japanese][id]
+}
@@ -0,0 +1,60 @@
+package mydject
+
+import "reflect"
+
+type (
+	// Optional は登録が存在しない場合もエラーにならない依存関係です。
+	// コンストラクタや Invoke の引数に Optional[T] を指定すると、T が登録されていない場合は Get が false を返します
+	Optional[T any] struct {
+		value T
+		ok    bool
+	}
+	// wrapper はコンテナが登録を使わずに解決する型です。ラップされた型の登録から生成します
+	wrapper interface {
+		// wrappedKey はラップされた型の登録のキーを返します
+		wrappedKey(k key) key
+		// isOptional はラップされた型の登録が存在しなくても解決できるかどうかを返します
+		isOptional() bool
+		// isDeferred はラップされた型の解決を遅延するかどうかを返します。遅延する依存関係は循環参照になりません
+		isDeferred() bool
+		resolveWrapper(c *container, k key, inv *invocation) (reflect.Value, error)
+	}
+)
+
+var wrapperType = reflect.TypeOf((*wrapper)(nil)).Elem()
+
+// Get は解決されたインスタンスを返します。登録が存在しない場合はゼロ値と false を返します
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+func (Optional[T]) wrappedKey(k key) key {
+	return key{t: As[T](), name: k.name}
+}
+func (Optional[T]) isOptional() bool {
+	return true
+}
+func (Optional[T]) isDeferred() bool {
+	return false
+}
+func (o Optional[T]) resolveWrapper(c *container, k key, inv *invocation) (reflect.Value, error) {
+	inner := o.wrappedKey(k)
+	if !c.isRegistered(inner) {
+		return reflect.ValueOf(Optional[T]{}), nil
+	}
+	v, err := c.resolve(inner, inv)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	result := Optional[T]{ok: true}
+	reflect.ValueOf(&result.value).Elem().Set(*v)
+	return reflect.ValueOf(result), nil
+}
+
+// asWrapper は t がコンテナが登録を使わずに解決する型の場合に wrapper を返します
+func asWrapper(t reflect.Type) (wrapper, bool) {
+	if t.Kind() == reflect.Interface || !t.Implements(wrapperType) {
+		return nil, false
+	}
+	return reflect.Zero(t).Interface().(wrapper), true
+}
@@ -0,0 +1,94 @@
+package mydject
+
+import "reflect"
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+type (
+	// output はコンストラクタの返り値のうち、個別に登録するものです
+	output struct {
+		t     reflect.Type
+		index int
+		field []int
+		name  string
+		group bool
+	}
+)
+
+// getOutputs はコンストラクタの error と後処理の関数以外の全ての返り値を返します
+func getOutputs(f factoryInfo, option RegisterOptions) []output {
+	t := f.target.Type()
+	var outs []output
+	for i := 0; i < t.NumOut(); i++ {
+		if i == f.cleanupIndex && i > 0 || i == t.NumOut()-1 && t.Out(i) == errorType {
+			continue
+		}
+		outs = append(outs, output{t: t.Out(i), index: i, name: option.Name, group: option.Group})
+	}
+	return outs
+}
+
+// getOutFields は Out を埋め込んだ構造体の返り値の各フィールドを返します
+func getOutFields(t reflect.Type, option RegisterOptions) ([]output, error) {
+	var outs []output
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type == outType {
+			continue
+		}
+		if !field.IsExported() {
+			return nil, ErrUnexportedInjectField
+		}
+		tag, err := parseInjectTag(field.Tag.Get(injectTagName))
+		if err != nil || tag.optional {
+			return nil, ErrInvalidInjectTag
+		}
+		o := output{t: field.Type, field: field.Index, name: option.Name, group: option.Group || tag.group}
+		if tag.name != "" {
+			o.name = tag.name
+		}
+		outs = append(outs, o)
+	}
+	return outs, nil
+}
+
+// registerOutputs はコンストラクタの返り値をそれぞれの型で登録します。c.mu をロックして呼び出します
+func (c *container) registerOutputs(reg *registration, target Target, f factoryInfo, option RegisterOptions, outs []output) error {
+	// 登録する前に全ての返り値と Interfaces を検証します
+	bound := make(map[int]bool)
+	interfaces := make([]int, len(option.Interfaces))
+	for i, p := range option.Interfaces {
+		interfaces[i] = -1
+		for j, o := range outs {
+			if o.t.AssignableTo(p) {
+				interfaces[i] = j
+				bound[j] = true
+				break
+			}
+		}
+		if interfaces[i] < 0 {
+			return c.newRegistrationError(target, &InterfaceError{Type: f.target.Type(), Interface: p, Err: c.localize(ErrNotImplemented), lang: c.options.Language})
+		}
+	}
+	for j, o := range outs {
+		if o.t.Kind() == reflect.Ptr && !bound[j] {
+			return c.newRegistrationError(target, ErrNeedInterfaceOnPointerRegistering)
+		}
+	}
+
+	f.producer = &producer{target: f.target}
+	f.interceptors = option.Interceptors
+	for i, p := range option.Interfaces {
+		o := outs[interfaces[i]]
+		f.outIndex, f.outField = o.index, o.field
+		c.add(reg, key{t: p, name: o.name}, f, o.group)
+	}
+	f.interceptors = nil
+	for _, o := range outs {
+		if o.t.Kind() != reflect.Ptr {
+			f.outIndex, f.outField = o.index, o.field
+			c.add(reg, key{t: o.t, name: o.name}, f, o.group)
+		}
+	}
+	return nil
+}
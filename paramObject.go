@@ -0,0 +1,109 @@
+package mydject
+
+import (
+	"reflect"
+	"sync"
+)
+
+type (
+	// In を埋め込んだ構造体をコンストラクタや Invoke の引数にすると、各フィールドが個別に解決されます。
+	// フィールドには inject タグで name=<名前>、optional、group を指定できます
+	In struct{}
+	// Out を埋め込んだ構造体をコンストラクタの返り値にすると、各フィールドがそれぞれの型で登録されます。
+	// フィールドには inject タグで name=<名前>、group を指定できます
+	Out      struct{}
+	inFields struct {
+		fields []param
+		err    error
+	}
+)
+
+var (
+	inType  = reflect.TypeOf(In{})
+	outType = reflect.TypeOf(Out{})
+	// inFieldsCache は In を埋め込んだ構造体の型ごとの解析結果です
+	inFieldsCache sync.Map
+)
+
+// embeds は t が marker を埋め込んだ構造体かどうかを返します
+func embeds(t reflect.Type, marker reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Anonymous && field.Type == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// getInFields は In を埋め込んだ構造体の各フィールドの依存関係を返します。In を埋め込んでいない場合は ok が false です
+func getInFields(t reflect.Type) (fields []param, ok bool, err error) {
+	if !embeds(t, inType) {
+		return nil, false, nil
+	}
+	if cached, ok := inFieldsCache.Load(t); ok {
+		f := cached.(inFields)
+		return f.fields, true, f.err
+	}
+	fields, err = parseInFields(t)
+	inFieldsCache.Store(t, inFields{fields: fields, err: err})
+	return fields, true, err
+}
+func parseInFields(t reflect.Type) ([]param, error) {
+	fields := []param{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type == inType {
+			continue
+		}
+		if !field.IsExported() {
+			return nil, ErrUnexportedInjectField
+		}
+		p, err := newFieldParam(field, i)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, p)
+	}
+	return fields, nil
+}
+
+// getArgParams はコンストラクタや Invoke の引数の依存関係を返します。In を埋め込んだ構造体は各フィールドに展開されます。
+// variadic の場合、最後の引数はグループとして解決されます
+func getArgParams(ins []reflect.Type, variadic bool) ([]param, error) {
+	params := make([]param, 0, len(ins))
+	for i, in := range ins {
+		if variadic && i == len(ins)-1 {
+			params = append(params, param{key: key{t: in}, group: true})
+			continue
+		}
+		fields, ok, err := getInFields(in)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			params = append(params, param{key: key{t: in}})
+			continue
+		}
+		params = append(params, fields...)
+	}
+	return params, nil
+}
+
+// resolveArg はコンストラクタや Invoke の引数を解決します
+func (c *container) resolveArg(t reflect.Type, inv *invocation) (*reflect.Value, error) {
+	fields, ok, err := getInFields(t)
+	if err != nil {
+		return nil, c.localize(err)
+	}
+	if !ok {
+		return c.resolve(key{t: t}, inv)
+	}
+	v := reflect.New(t).Elem()
+	if err := c.injectFields(v, fields, inv); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
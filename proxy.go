@@ -0,0 +1,115 @@
+package mydject
+
+import (
+	"reflect"
+	"sync"
+)
+
+type (
+	// Interceptor はプロキシを経由したメソッド呼び出しに割り込むフックです。
+	// Before、Around、After の順に呼び出され、nil のフックは呼び出されません
+	Interceptor struct {
+		// Before はメソッドの呼び出し前に呼び出されます
+		Before func(method string, args []interface{})
+		// Around はメソッドの呼び出しを包みます。proceed を呼び出すと次のインターセプタまたはメソッドが呼び出されます
+		Around func(method string, args []interface{}, proceed func() []interface{}) []interface{}
+		// After はメソッドの呼び出し後に返り値と共に呼び出されます
+		After func(method string, args []interface{}, results []interface{})
+	}
+	// ProxyHandler はプロキシのメソッド呼び出しをインターセプタを経由して元のインスタンスに渡します。
+	// mydject-proxy で生成したプロキシから呼び出されます
+	ProxyHandler struct {
+		target       reflect.Value
+		interceptors []Interceptor
+	}
+	proxyFactory func(h *ProxyHandler) reflect.Value
+)
+
+// proxies はインターフェイスの型ごとのプロキシの生成関数です
+var proxies sync.Map
+
+// RegisterProxy はインターフェイス I のプロキシの生成関数を登録します。mydject-proxy で生成したコードから呼び出されます
+func RegisterProxy[I any](factory func(h *ProxyHandler) I) {
+	proxies.Store(As[I](), proxyFactory(func(h *ProxyHandler) reflect.Value {
+		return reflect.ValueOf(factory(h))
+	}))
+}
+
+// validateProxies はインターセプタを適用する全てのインターフェイスのプロキシが登録されていることを検証します
+func validateProxies(interfaces []reflect.Type) error {
+	if len(interfaces) == 0 {
+		return ErrProxyNotFound
+	}
+	for _, t := range interfaces {
+		if _, ok := getProxyFactory(t); !ok {
+			return ErrProxyNotFound
+		}
+	}
+	return nil
+}
+
+func getProxyFactory(t reflect.Type) (proxyFactory, bool) {
+	f, ok := proxies.Load(t)
+	if !ok {
+		return nil, false
+	}
+	return f.(proxyFactory), true
+}
+
+// newProxy は v を包み、インターセプタを経由してメソッドを呼び出すプロキシを生成します
+func newProxy(t reflect.Type, v reflect.Value, interceptors []Interceptor) reflect.Value {
+	factory, _ := getProxyFactory(t)
+	proxy := reflect.New(t).Elem()
+	proxy.Set(factory(&ProxyHandler{target: v, interceptors: interceptors}))
+	return proxy
+}
+
+// Call はインターセプタを登録順に経由してメソッドを呼び出し、返り値を返します。可変長引数はスライスで渡します
+func (h *ProxyHandler) Call(method string, args ...interface{}) []interface{} {
+	call := func() []interface{} {
+		return h.call(method, args)
+	}
+	for i := len(h.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := h.interceptors[i], call
+		call = func() []interface{} {
+			if interceptor.Before != nil {
+				interceptor.Before(method, args)
+			}
+			var results []interface{}
+			if interceptor.Around != nil {
+				results = interceptor.Around(method, args, next)
+			} else {
+				results = next()
+			}
+			if interceptor.After != nil {
+				interceptor.After(method, args, results)
+			}
+			return results
+		}
+	}
+	return call()
+}
+
+func (h *ProxyHandler) call(method string, args []interface{}) []interface{} {
+	m := h.target.MethodByName(method)
+	t := m.Type()
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		if arg == nil {
+			in[i] = reflect.Zero(t.In(i))
+		} else {
+			in[i] = reflect.ValueOf(arg)
+		}
+	}
+	var outs []reflect.Value
+	if t.IsVariadic() {
+		outs = m.CallSlice(in)
+	} else {
+		outs = m.Call(in)
+	}
+	results := make([]interface{}, len(outs))
+	for i, out := range outs {
+		results[i] = out.Interface()
+	}
+	return results
+}
@@ -1,7 +1,9 @@
 package mydject
 
 import (
+	"context"
 	"reflect"
+	"runtime"
 )
 
 func getIns(t reflect.Type) []reflect.Type {
@@ -19,7 +21,60 @@ func getOut(t reflect.Type) (reflect.Type, error) {
 	}
 	return t.Out(0), nil
 }
+
+var (
+	contextType          = reflect.TypeOf((*context.Context)(nil)).Elem()
+	cleanupFuncType      = reflect.TypeOf((func())(nil))
+	cleanupErrorFuncType = reflect.TypeOf((func() error)(nil))
+)
+
+// getCleanupIndex はコンストラクタの2番目以降の返り値のうち、func() または func() error の最初の位置を返します。存在しない場合は 0 です
+func getCleanupIndex(t reflect.Type) int {
+	for i := 1; i < t.NumOut(); i++ {
+		if out := t.Out(i); out == cleanupFuncType || out == cleanupErrorFuncType {
+			return i
+		}
+	}
+	return 0
+}
+
+// toCleanup はコンストラクタが返した後処理の関数を func() error に変換します
+func toCleanup(v reflect.Value) func() error {
+	if v.IsNil() {
+		return nil
+	}
+	switch fn := v.Interface().(type) {
+	case func():
+		return func() error {
+			fn()
+			return nil
+		}
+	case func() error:
+		return fn
+	}
+	return nil
+}
+
+// isNilTarget は登録する対象が nil、nil の関数または nil のポインタかどうかを返します
+func isNilTarget(target Target) bool {
+	if target == nil {
+		return true
+	}
+	v := reflect.ValueOf(target)
+	switch v.Kind() {
+	case reflect.Func, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
 func getTargetReflectionInfos(target Target) (out reflect.Type, in []reflect.Type, err error) {
+	if st, ok := target.(structTarget); ok {
+		if _, ok := structType(st.t); !ok {
+			return nil, nil, ErrRequireStruct
+		}
+		return st.t, []reflect.Type{}, nil
+	}
 	t := reflect.TypeOf(target)
 	if t.Kind() == reflect.Func {
 		out, err := getOut(t)
@@ -31,3 +86,22 @@ func getTargetReflectionInfos(target Target) (out reflect.Type, in []reflect.Typ
 	}
 	return t, nil, nil
 }
+func getFuncLocation(v reflect.Value) (name string, file string, line int) {
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return "", "", 0
+	}
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		return "", "", 0
+	}
+	file, line = fn.FileLine(fn.Entry())
+	return fn.Name(), file, line
+}
+
+// callFunc は関数を呼び出します。可変長引数の関数の場合は最後の引数をスライスのまま渡します
+func callFunc(fn reflect.Value, args []reflect.Value) []reflect.Value {
+	if fn.Type().IsVariadic() {
+		return fn.CallSlice(args)
+	}
+	return fn.Call(args)
+}
@@ -9,5 +9,16 @@ type (
 	RegisterOptions struct {
 		LifetimeScope LifetimeScope
 		Interfaces    []reflect.Type
+		// Name を指定した場合、名前付きで登録されます。名前付きの登録は ResolveNamed で解決します
+		Name string
+		// Group の場合、同じ型の登録を上書きせずにグループのメンバーとして追加します。
+		// []T を要求すると T のグループのメンバーが登録順に解決されます
+		Group bool
+		// MultipleOutputs の場合、コンストラクタの error と後処理の関数以外の全ての返り値をそれぞれの型で登録します。
+		// Interfaces は最初に代入できる返り値に対して登録されます。コンストラクタの呼び出しは全ての返り値で共有されます
+		MultipleOutputs bool
+		// Interceptors を指定した場合、Interfaces で解決されるインスタンスをプロキシで包み、全てのメソッドの呼び出しにインターセプタを適用します。
+		// Interfaces の各インターフェイスのプロキシを mydject-proxy で生成しておく必要があります
+		Interceptors []Interceptor
 	}
 )
@@ -0,0 +1,203 @@
+package mydject
+
+import (
+	"reflect"
+	"sort"
+)
+
+type (
+	// registry は登録されたコンポーネントの一覧です
+	registry struct {
+		factoryInfos map[key]factoryInfo
+		// groups はグループとして登録されたコンポーネントを登録順に保持します
+		groups map[key][]factoryInfo
+		// decorators は型ごとのデコレータを登録順に保持します
+		decorators map[reflect.Type][]decorator
+		// dependents は依存関係を解決しうる登録のキーごとに、それに依存する登録のキーを保持します。
+		// 登録を上書きまたは取り消した後も残るため、依存関係が存在するかは params で確認します
+		dependents map[key]map[key]struct{}
+		// decoratorDependents は依存関係を解決しうる登録のキーごとに、それに依存するデコレータが適用される型を保持します
+		decoratorDependents map[key]map[reflect.Type]struct{}
+	}
+)
+
+func newRegistry() *registry {
+	return &registry{
+		factoryInfos:        make(map[key]factoryInfo),
+		groups:              make(map[key][]factoryInfo),
+		decorators:          make(map[reflect.Type][]decorator),
+		dependents:          make(map[key]map[key]struct{}),
+		decoratorDependents: make(map[key]map[reflect.Type]struct{}),
+	}
+}
+
+func (r *registry) clone() *registry {
+	cloned := newRegistry()
+	for k, f := range r.factoryInfos {
+		cloned.factoryInfos[k] = f
+	}
+	for k, fs := range r.groups {
+		// 子コンテナでの追加が親コンテナに影響しないように複製します
+		cloned.groups[k] = append([]factoryInfo{}, fs...)
+	}
+	for t, ds := range r.decorators {
+		cloned.decorators[t] = append([]decorator{}, ds...)
+	}
+	for k, consumers := range r.dependents {
+		cloned.dependents[k] = make(map[key]struct{}, len(consumers))
+		for consumer := range consumers {
+			cloned.dependents[k][consumer] = struct{}{}
+		}
+	}
+	for k, types := range r.decoratorDependents {
+		cloned.decoratorDependents[k] = make(map[reflect.Type]struct{}, len(types))
+		for t := range types {
+			cloned.decoratorDependents[k][t] = struct{}{}
+		}
+	}
+	return cloned
+}
+
+func (r *registry) isEmpty() bool {
+	return len(r.factoryInfos) == 0 && len(r.groups) == 0
+}
+
+// add は登録を追加し、キャッシュから取り除くべきキーを返します
+func (r *registry) add(k key, f factoryInfo, group bool) key {
+	if group {
+		r.groups[k] = append(r.groups[k], f)
+		k = k.member(len(r.groups[k]))
+	} else {
+		r.factoryInfos[k] = f
+	}
+	for _, p := range f.params() {
+		for _, d := range dependencyIndexKeys(p.key) {
+			if r.dependents[d] == nil {
+				r.dependents[d] = make(map[key]struct{})
+			}
+			r.dependents[d][k] = struct{}{}
+		}
+	}
+	return k
+}
+
+func (r *registry) decorate(t reflect.Type, d decorator) {
+	r.decorators[t] = append(r.decorators[t], d)
+	for _, p := range d.params() {
+		for _, dk := range dependencyIndexKeys(p.key) {
+			if r.decoratorDependents[dk] == nil {
+				r.decoratorDependents[dk] = make(map[reflect.Type]struct{})
+			}
+			r.decoratorDependents[dk][t] = struct{}{}
+		}
+	}
+}
+
+// dependencyIndexKeys は依存関係 k を解決しうる登録のキーを返します。Lazy や Provider などで遅延して解決されるものは含みません
+func dependencyIndexKeys(k key) []key {
+	keys := []key{k}
+	if k.t.Kind() == reflect.Slice {
+		keys = append(keys, key{t: k.t.Elem(), name: k.name})
+	}
+	w, ok := asWrapper(k.t)
+	if !ok {
+		w, ok = asProviderFunc(k.t)
+	}
+	if ok && !w.isDeferred() {
+		keys = append(keys, dependencyIndexKeys(w.wrappedKey(k))...)
+	}
+	return keys
+}
+
+// dependentsOf は k の登録に依存しうる登録のキーを安定した順序で返します。デコレータが依存している場合はデコレートされる全ての登録を含みます
+func (r *registry) dependentsOf(k key) []key {
+	if k.index != 0 {
+		k = k.group()
+	}
+	var keys []key
+	for consumer := range r.dependents[k] {
+		keys = append(keys, consumer)
+	}
+	for t := range r.decoratorDependents[k] {
+		keys = append(keys, r.keysOf(t)...)
+	}
+	sortKeys(keys)
+	return keys
+}
+
+// keysOf は型が t の全ての登録のキーを、グループのメンバーを含めて返します
+func (r *registry) keysOf(t reflect.Type) []key {
+	var keys []key
+	for k := range r.factoryInfos {
+		if k.t == t {
+			keys = append(keys, k)
+		}
+	}
+	for g, members := range r.groups {
+		if g.t == t {
+			for i := range members {
+				keys = append(keys, g.member(i+1))
+			}
+		}
+	}
+	sortKeys(keys)
+	return keys
+}
+
+// params は k の登録を解決するときに必要な依存関係を、デコレータの依存関係を含めて返します
+func (r *registry) params(k key, f factoryInfo) []param {
+	params := f.params()
+	for _, d := range r.decorators[k.t] {
+		params = append(params, d.params()...)
+	}
+	return params
+}
+
+// get はキーに対応する登録を返します。グループのメンバーのキーにも対応します
+func (r *registry) get(k key) (factoryInfo, bool) {
+	if k.index == 0 {
+		f, ok := r.factoryInfos[k]
+		return f, ok
+	}
+	members := r.groups[k.group()]
+	if k.index > len(members) {
+		return factoryInfo{}, false
+	}
+	return members[k.index-1], true
+}
+
+// members は []T を要求されたときに集めるグループのメンバーのキーを登録順に返します
+func (r *registry) members(k key) ([]key, bool) {
+	if k.t.Kind() != reflect.Slice {
+		return nil, false
+	}
+	g := key{t: k.t.Elem(), name: k.name}
+	members, ok := r.groups[g]
+	if !ok {
+		return nil, false
+	}
+	keys := make([]key, len(members))
+	for i := range members {
+		keys[i] = g.member(i + 1)
+	}
+	return keys, true
+}
+
+// keys は全ての登録のキーを、グループのメンバーを含めて安定した順序で返します
+func (r *registry) keys() []key {
+	keys := make([]key, 0, len(r.factoryInfos))
+	for k := range r.factoryInfos {
+		keys = append(keys, k)
+	}
+	for g, members := range r.groups {
+		for i := range members {
+			keys = append(keys, g.member(i+1))
+		}
+	}
+	sortKeys(keys)
+	return keys
+}
+
+func sortKeys(keys []key) {
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+}
@@ -0,0 +1,109 @@
+package mydject
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+type (
+	// Scope は BeginScope で開始したスコープです。
+	// ScopeManaged なインスタンスはスコープ内の全ての呼び出しで共有され、Close で破棄されます。
+	// 複数の goroutine から並行して利用できます
+	Scope interface {
+		ServiceLocator
+		Close() error
+	}
+	scope struct {
+		container *container
+		mu        sync.Mutex
+		cache     map[key]*instance
+		created   []createdInstance
+	}
+	// scopeContextKey は context にスコープを保持するキーです
+	scopeContextKey struct{}
+)
+
+// BeginScope は新しいスコープを開始します。HTTP リクエストやジョブごとに開始し、終了時に Close してください
+func (c *container) BeginScope() Scope {
+	return newScope(c)
+}
+func newScope(c *container) *scope {
+	return &scope{container: c, cache: make(map[key]*instance)}
+}
+
+// WithScope はスコープを保持する context を返します。
+// この context を InvokeContext に渡すと、コンテナの InvokeContext でもスコープの中で解決されます
+func WithScope(ctx context.Context, s Scope) context.Context {
+	return context.WithValue(ctx, scopeContextKey{}, s)
+}
+
+// ScopeFromContext は ctx が保持するスコープを返します。保持していない場合は nil です
+func ScopeFromContext(ctx context.Context) Scope {
+	s, _ := ctx.Value(scopeContextKey{}).(Scope)
+	return s
+}
+
+// Invoke はスコープの中でインスタンスを解決して呼び出します
+func (s *scope) Invoke(invoker Invoker) error {
+	return s.container.invoke(invoker, newInvocation(WithScope(context.Background(), s)))
+}
+
+// InvokeContext はスコープの中でインスタンスを解決して呼び出します。ctx はスコープを保持してコンストラクタに渡されます
+func (s *scope) InvokeContext(ctx context.Context, invoker Invoker) error {
+	return s.container.invoke(invoker, newInvocation(WithScope(ctx, s)))
+}
+
+// Call はスコープの中でインスタンスを解決して呼び出し、invoker の返り値を返します
+func (s *scope) Call(invoker Invoker) ([]reflect.Value, error) {
+	return s.container.call(invoker, newInvocation(WithScope(context.Background(), s)))
+}
+
+// ResolveNamed はスコープの中で名前を指定して登録されたコンポーネントを解決し、target が指す変数に設定します
+func (s *scope) ResolveNamed(name string, target interface{}) error {
+	return s.container.resolveNamed(name, target, newInvocation(WithScope(context.Background(), s)))
+}
+
+// InjectInto はスコープの中で target が指す構造体の inject タグの付いたフィールドに依存関係を注入します
+func (s *scope) InjectInto(target interface{}) error {
+	return s.container.injectInto(target, newInvocation(WithScope(context.Background(), s)))
+}
+
+// Verify はスコープの中で登録された全てのコンポーネントが解決できることを検証します
+func (s *scope) Verify(options ...VerifyOptions) error {
+	c := s.container
+	if len(options) > 1 {
+		return c.localize(ErrNoMultipleOption)
+	}
+	if len(options) == 1 && options[0].DryRun {
+		return c.verifyStatic()
+	}
+	return c.verify(newInvocation(WithScope(context.Background(), s)))
+}
+
+// Close はこのスコープで生成した ScopeManaged なインスタンスを生成の逆順に破棄します。
+// 破棄した後にスコープを利用した場合はインスタンスを再生成します
+func (s *scope) Close() error {
+	s.mu.Lock()
+	created := s.created
+	s.created = nil
+	s.cache = make(map[key]*instance)
+	s.mu.Unlock()
+	return disposeAll(created, s.container.options.Language)
+}
+
+func (s *scope) getInstance(k key) *instance {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	inst, ok := s.cache[k]
+	if !ok {
+		inst = &instance{}
+		s.cache[k] = inst
+	}
+	return inst
+}
+func (s *scope) track(k key, inst *instance) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.created = append(s.created, createdInstance{key: k, instance: inst})
+}
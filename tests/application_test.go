@@ -0,0 +1,116 @@
+package djecttest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ohishikaito/mydject"
+)
+
+func Test_Application(t *testing.T) {
+	singleton := mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}
+	setup := func(t *testing.T, log *[]string) mydject.Container {
+		sut := mydject.NewContainer()
+		hook := func(name string) mydject.Hook {
+			return mydject.Hook{
+				OnStart: func(ctx context.Context) error {
+					*log = append(*log, "start "+name)
+					return nil
+				},
+				OnStop: func(ctx context.Context) error {
+					*log = append(*log, "stop "+name)
+					return nil
+				},
+			}
+		}
+		if err := sut.Register(func(lc mydject.Lifecycle) Service1 {
+			lc.Append(hook("service1"))
+			return NewService1()
+		}, singleton); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(lc mydject.Lifecycle, service1 Service1) Service2 {
+			lc.Append(hook("service2"))
+			return NewService2()
+		}, singleton); err != nil {
+			t.Fatal(err)
+		}
+		return sut
+	}
+	t.Run("OnStart を依存関係の順に OnStop を逆順に呼び出すこと", func(t *testing.T) {
+		var log []string
+		app, err := mydject.NewApplication(setup(t, &log), func(service2 Service2) {})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := app.Start(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if err := app.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"start service1", "start service2", "stop service2", "stop service1"}
+		if !reflect.DeepEqual(log, want) {
+			t.Fatal(log)
+		}
+	})
+	t.Run("OnStart がタイムアウトした場合", func(t *testing.T) {
+		var log []string
+		sut := setup(t, &log)
+		if err := sut.Register(func(lc mydject.Lifecycle) Service3 {
+			lc.Append(mydject.Hook{OnStart: func(ctx context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			}})
+			return NewService3()
+		}, singleton); err != nil {
+			t.Fatal(err)
+		}
+		app, err := mydject.NewApplication(sut, func(service2 Service2, service3 Service3) {})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err = app.Start(ctx)
+		var herr *mydject.HookError
+		if !errors.As(err, &herr) || herr.Event != "OnStart" || herr.Line == 0 || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatal(err)
+		}
+		if err := app.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"start service1", "start service2", "stop service2", "stop service1"}
+		if !reflect.DeepEqual(log, want) {
+			t.Fatal(log)
+		}
+	})
+	t.Run("Run はシグナルを受け取るまで待つこと", func(t *testing.T) {
+		var log []string
+		sut := setup(t, &log)
+		if err := sut.Register(func(lc mydject.Lifecycle) Service3 {
+			lc.Append(mydject.Hook{OnStart: func(ctx context.Context) error {
+				return syscall.Kill(os.Getpid(), syscall.SIGTERM)
+			}})
+			return NewService3()
+		}, singleton); err != nil {
+			t.Fatal(err)
+		}
+		app, err := mydject.NewApplication(sut, func(service2 Service2, service3 Service3) {})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := app.Run(); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"start service1", "start service2", "stop service2", "stop service1"}
+		if !reflect.DeepEqual(log, want) {
+			t.Fatal(log)
+		}
+	})
+}
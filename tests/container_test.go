@@ -2,19 +2,32 @@ package djecttest
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ohishikaito/mydject"
 )
 
+func isConstructorError(err error, message string) bool {
+	var cerr *mydject.ConstructorError
+	return errors.As(err, &cerr) && cerr.Err.Error() == message
+}
+func isRegistrationError(err error, target error) bool {
+	var rerr *mydject.RegistrationError
+	return errors.As(err, &rerr) && errors.Is(err, target)
+}
 func Test_container_Invoke(t *testing.T) {
 	t.Run("最後尾の引数がエラーで nil でない場合", func(t *testing.T) {
 		sut := mydject.NewContainer()
 		if err := sut.Register(NewService1With2WithError); err != nil {
 			t.Fatal()
 		}
-		if err := sut.Invoke(func(service1 Service1) {}); err == nil || err.Error() != "NewService1With2WithError Error" {
+		if err := sut.Invoke(func(service1 Service1) {}); !isConstructorError(err, "NewService1With2WithError Error") {
 			t.Fatal()
 		}
 	})
@@ -150,14 +163,6 @@ func Test_container_Invoke(t *testing.T) {
 			t.Fatal(err)
 		}
 	})
-	t.Run("解決するオブジェクトが存在しない", func(t *testing.T) {
-		t.Parallel()
-		sut := mydject.NewContainer()
-		err := sut.Invoke(func() {})
-		if err == nil || err != mydject.ErrNotFoundComponent {
-			t.Fatal(err)
-		}
-	})
 	t.Run("指定されたタイプを解決できない", func(t *testing.T) {
 		t.Parallel()
 		sut := mydject.NewContainer()
@@ -198,7 +203,7 @@ func Test_container_Invoke(t *testing.T) {
 			if err := sut.Register(NewService1With2WithError); err != nil {
 				t.Fatal()
 			}
-			if err := sut.Invoke(func(service1 Service1) {}); err.Error() != "NewService1With2WithError Error" {
+			if err := sut.Invoke(func(service1 Service1) {}); !isConstructorError(err, "NewService1With2WithError Error") {
 				t.Fatal()
 			}
 		})
@@ -207,12 +212,33 @@ func Test_container_Invoke(t *testing.T) {
 			if err := sut.Register(NewService1With2WithError, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
 				t.Fatal()
 			}
-			if err := sut.Invoke(func(service1 Service1) {}); err.Error() != "NewService1With2WithError Error" {
+			if err := sut.Invoke(func(service1 Service1) {}); !isConstructorError(err, "NewService1With2WithError Error") {
 				t.Fatal()
 			}
 		})
 	})
 }
+func Test_container_Transient(t *testing.T) {
+	t.Run("LifetimeScope が Transient の場合 要求されるたびに異なるインスタンスが生成されること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+
+		if err := sut.Register(NewService1, mydject.RegisterOptions{LifetimeScope: mydject.Transient}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(service1 Service1) Service2 {
+			return &service2{id: service1.GetID()}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(a Service1, b Service1, service2 Service2) {
+			if a.GetID() == b.GetID() || a.GetID() == service2.GetID() || b.GetID() == service2.GetID() {
+				t.Fatal(a.GetID(), b.GetID(), service2.GetID())
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
 func Test_container_CreateChildContainer(t *testing.T) {
 	t.Run("コンポーネントの Invoke の状態を継承すること", func(t *testing.T) {
 		type result struct {
@@ -435,7 +461,7 @@ func Test_container_Register(t *testing.T) {
 		sut := mydject.NewContainer()
 		err := sut.Register(func() {
 		})
-		if err == nil || err != mydject.ErrRequireResponse {
+		if !isRegistrationError(err, mydject.ErrRequireResponse) {
 			t.Fatal(err)
 		}
 	})
@@ -446,17 +472,43 @@ func Test_container_Register(t *testing.T) {
 		err := sut.Register(func() string {
 			return ""
 		}, opt1, opt2)
-		if err == nil || err != mydject.ErrNoMultipleOption {
+		if !isRegistrationError(err, mydject.ErrNoMultipleOption) {
 			t.Fatal(err)
 		}
 	})
 	t.Run("ポインタを登録する場合は、インターフェイスを指定する必要があること", func(t *testing.T) {
 		sut := mydject.NewContainer()
 		err := sut.Register(NewService3())
-		if err == nil || err != mydject.ErrNeedInterfaceOnPointerRegistering {
+		if !isRegistrationError(err, mydject.ErrNeedInterfaceOnPointerRegistering) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Interfaces のインターフェイスを実装していない場合", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		err := sut.Register(NewService3(), mydject.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*UseCase)(nil)).Elem()}})
+		var ierr *mydject.InterfaceError
+		if !isRegistrationError(err, mydject.ErrNotImplemented) || !errors.As(err, &ierr) ||
+			ierr.Interface != reflect.TypeOf((*UseCase)(nil)).Elem() {
 			t.Fatal(err)
 		}
 	})
+	t.Run("Interfaces にインターフェイス以外が指定された場合", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		for _, ifs := range [][]reflect.Type{{reflect.TypeOf("")}, {nil}} {
+			if err := sut.Register(NewService1, mydject.RegisterOptions{Interfaces: ifs}); !isRegistrationError(err, mydject.ErrRequireInterface) {
+				t.Fatal(err)
+			}
+		}
+	})
+	t.Run("nil を登録しようとした場合", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		var constructor func() Service1
+		for _, target := range []mydject.Target{nil, constructor} {
+			if err := sut.Register(target); !isRegistrationError(err, mydject.ErrNilTarget) {
+				t.Fatal(err)
+			}
+		}
+	})
 }
 func Test_container_Verify(t *testing.T) {
 	t.Run("Verify できること1", func(t *testing.T) {
@@ -510,3 +562,755 @@ func Test_container_Verify(t *testing.T) {
 		}
 	})
 }
+func Test_container_Concurrency(t *testing.T) {
+	t.Run("ContainerManaged のコンストラクタは並行に解決しても1度だけ呼ばれること", func(t *testing.T) {
+		t.Parallel()
+		sut := mydject.NewContainer()
+		var count int32
+		if err := sut.Register(func() Service2 {
+			atomic.AddInt32(&count, 1)
+			return NewService2()
+		}, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]string, 100)
+		var wg sync.WaitGroup
+		for i := range ids {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if err := sut.Invoke(func(service1 Service1, service2 Service2) {
+					ids[i] = service2.GetID()
+				}); err != nil {
+					t.Error(err)
+				}
+			}(i)
+		}
+		wg.Wait()
+		if count != 1 {
+			t.Fatal(count)
+		}
+		for _, id := range ids {
+			if id != ids[0] {
+				t.Fatal(id, ids[0])
+			}
+		}
+	})
+	t.Run("Register と Invoke と CreateChildContainer を並行に呼び出せること", func(t *testing.T) {
+		t.Parallel()
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(3)
+			go func() {
+				defer wg.Done()
+				if err := sut.Register(NewService2, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+					t.Error(err)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				if err := sut.Invoke(func(service1 Service1) {}); err != nil {
+					t.Error(err)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				if err := sut.CreateChildContainer().Invoke(func(service1 Service1) {}); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+func Test_container_CircularDependency(t *testing.T) {
+	setup := func(t *testing.T, options ...mydject.RegisterOptions) mydject.Container {
+		sut := mydject.NewContainer()
+		for _, ctor := range []interface{}{NewCircularA, NewCircularB, NewCircularC} {
+			if err := sut.Register(ctor, options...); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return sut
+	}
+	chk := func(t *testing.T, err error) {
+		var cerr *mydject.CircularDependencyError
+		if !errors.As(err, &cerr) {
+			t.Fatal(err)
+		}
+		if len(cerr.Path) != 4 || cerr.Path[0].Type != cerr.Path[3].Type {
+			t.Fatal(err)
+		}
+		for _, d := range cerr.Path {
+			if !strings.HasSuffix(d.File, "mock.go") || d.Line == 0 || d.Constructor == "" {
+				t.Fatal(d)
+			}
+		}
+		if !strings.Contains(err.Error(), "djecttest.CircularA -> djecttest.CircularB -> djecttest.CircularC -> djecttest.CircularA") {
+			t.Fatal(err)
+		}
+	}
+	t.Run("Invoke 時に循環参照を検出すること", func(t *testing.T) {
+		sut := setup(t)
+		chk(t, sut.Invoke(func(a CircularA) {}))
+	})
+	t.Run("ContainerManaged の場合も循環参照を検出すること", func(t *testing.T) {
+		sut := setup(t, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged})
+		chk(t, sut.Invoke(func(a CircularA) {}))
+	})
+	t.Run("別の goroutine から逆の順序で解決した場合も循環参照を検出すること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		// 両方の goroutine が CircularA と CircularB の生成を始めるまで待ち合わせます
+		startedA, startedB := make(chan struct{}), make(chan struct{})
+		var onceA, onceB sync.Once
+		if err := sut.Register(func() Service1 {
+			onceA.Do(func() { close(startedA) })
+			<-startedB
+			return NewService1()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func() Service2 {
+			onceB.Do(func() { close(startedB) })
+			<-startedA
+			return NewService2()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		singleton := mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}
+		if err := sut.Register(func(service1 Service1, b CircularB) CircularA {
+			return &struct{}{}
+		}, singleton); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(service2 Service2, a CircularA) CircularB {
+			return &struct{}{}
+		}, singleton); err != nil {
+			t.Fatal(err)
+		}
+		errs := make(chan error, 2)
+		go func() {
+			errs <- sut.Invoke(func(a CircularA) {})
+		}()
+		go func() {
+			errs <- sut.Invoke(func(b CircularB) {})
+		}()
+		for i := 0; i < 2; i++ {
+			select {
+			case err := <-errs:
+				var cerr *mydject.CircularDependencyError
+				if !errors.As(err, &cerr) {
+					t.Fatal(err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("deadlock")
+			}
+		}
+	})
+	t.Run("Verify 時に循環参照を検出すること", func(t *testing.T) {
+		sut := setup(t)
+		var cerr *mydject.CircularDependencyError
+		if err := sut.Verify(); !errors.As(err, &cerr) {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_container_Verify_DryRun(t *testing.T) {
+	dryRun := mydject.VerifyOptions{DryRun: true}
+	t.Run("コンストラクタを呼び出さずに検証できること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		called := false
+		if err := sut.Register(func(service2 Service2) Service1 {
+			called = true
+			return NewService1()
+		}, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(dryRun); err != nil {
+			t.Fatal(err)
+		}
+		if called {
+			t.Fatal()
+		}
+	})
+	t.Run("全てのエラーがまとめて返されること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		for _, ctor := range []interface{}{NewUseCase, NewNestedService, NewCircularA, NewCircularB, NewCircularC} {
+			if err := sut.Register(ctor); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := sut.Register(NewService1, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Verify(dryRun)
+		var verr *mydject.VerifyError
+		if !errors.As(err, &verr) {
+			t.Fatal(err)
+		}
+		missing := 0
+		for _, e := range verr.Errors {
+			if mydject.IsErrInvalidResolveComponent(e) {
+				missing++
+			}
+		}
+		// UseCase と NestedService のそれぞれに Service2 と Service3 が不足しています
+		if missing != 4 {
+			t.Fatal(err)
+		}
+		var cerr *mydject.CircularDependencyError
+		if !errors.As(err, &cerr) || len(cerr.Path) != 4 {
+			t.Fatal(err)
+		}
+	})
+	t.Run("ライフタイムスコープの違反を検出すること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(func(service1 Service1) Service2 {
+			return NewService2()
+		}, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		var lerr *mydject.LifetimeViolationError
+		if err := sut.Verify(dryRun); !errors.As(err, &lerr) ||
+			lerr.Type != reflect.TypeOf((*Service2)(nil)).Elem() ||
+			lerr.Dependency != reflect.TypeOf((*Service1)(nil)).Elem() {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_errors(t *testing.T) {
+	t.Run("ResolveError は要求された型と依存関係の経路を持つこと", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewUseCase); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewNestedService); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Invoke(func(useCase UseCase) {})
+		var rerr *mydject.ResolveError
+		if !errors.As(err, &rerr) || !errors.Is(err, mydject.ErrInvalidResolveComponent) {
+			t.Fatal(err)
+		}
+		if rerr.Type != reflect.TypeOf((*Service2)(nil)).Elem() || len(rerr.Path) != 3 ||
+			rerr.Path[0].Type != reflect.TypeOf((*UseCase)(nil)).Elem() ||
+			rerr.Path[1].Type != reflect.TypeOf((*NestedService)(nil)).Elem() ||
+			!strings.HasSuffix(rerr.Path[1].Constructor, "NewNestedService") {
+			t.Fatal(err)
+		}
+	})
+	t.Run("ConstructorError はエラーを返したコンストラクタを持つこと", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		e := errors.New("constructor error")
+		if err := sut.Register(func() (Service1, error) { return nil, e }); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Invoke(func(service1 Service1) {})
+		var cerr *mydject.ConstructorError
+		if !errors.As(err, &cerr) || !errors.Is(err, e) ||
+			cerr.Type != reflect.TypeOf((*Service1)(nil)).Elem() || cerr.Constructor == "" || cerr.Line == 0 {
+			t.Fatal(err)
+		}
+	})
+	t.Run("英語のメッセージを選択できること", func(t *testing.T) {
+		sut := mydject.NewContainer(mydject.ContainerOptions{Language: mydject.English})
+		err := sut.Invoke("")
+		if !errors.Is(err, mydject.ErrRequireFunction) || err.Error() != "a function must be specified" {
+			t.Fatal(err)
+		}
+		err = sut.CreateChildContainer().Invoke(func(service1 Service1) {})
+		if !mydject.IsErrInvalidResolveComponent(err) ||
+			err.Error() != "failed to resolve djecttest.Service1 (djecttest.Service1): the specified type cannot be resolved" {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_container_ResolveNamed(t *testing.T) {
+	setup := func(t *testing.T) mydject.Container {
+		sut := mydject.NewContainer()
+		ifs := []reflect.Type{reflect.TypeOf((*Service1)(nil)).Elem()}
+		if err := sut.Register(NewService1, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2(), mydject.RegisterOptions{Interfaces: ifs, Name: "primary"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService3(), mydject.RegisterOptions{Interfaces: ifs, Name: "replica"}); err != nil {
+			t.Fatal(err)
+		}
+		return sut
+	}
+	t.Run("名前を指定して解決できること", func(t *testing.T) {
+		sut := setup(t)
+		var primary, replica Service1
+		if err := sut.ResolveNamed("primary", &primary); err != nil || primary.GetName() != "service2" {
+			t.Fatal(err)
+		}
+		if err := sut.CreateChildContainer().ResolveNamed("replica", &replica); err != nil || replica.GetName() != "service3" {
+			t.Fatal(err)
+		}
+		if s, err := mydject.ResolveNamed[Service1](sut, "primary"); err != nil || s != primary {
+			t.Fatal(err)
+		}
+	})
+	t.Run("名前のない登録は従来どおり解決できること", func(t *testing.T) {
+		sut := setup(t)
+		if err := sut.Invoke(func(service1 Service1) {
+			if service1.GetName() != "service1" {
+				t.Fatal()
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		var s Service1
+		if err := sut.ResolveNamed("", &s); err != nil || s.GetName() != "service1" {
+			t.Fatal(err)
+		}
+	})
+	t.Run("存在しない名前の場合はエラーを返すこと", func(t *testing.T) {
+		sut := setup(t)
+		var s Service1
+		err := sut.ResolveNamed("unknown", &s)
+		var rerr *mydject.ResolveError
+		if !errors.As(err, &rerr) || rerr.Name != "unknown" || !mydject.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("ポインタ以外を指定した場合はエラーを返すこと", func(t *testing.T) {
+		sut := setup(t)
+		if err := sut.ResolveNamed("primary", nil); !errors.Is(err, mydject.ErrRequirePointer) {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_container_Group(t *testing.T) {
+	setup := func(t *testing.T) mydject.Container {
+		sut := mydject.NewContainer()
+		ifs := []reflect.Type{reflect.TypeOf((*Service1)(nil)).Elem()}
+		if err := sut.Register(NewService1, mydject.RegisterOptions{Group: true, LifetimeScope: mydject.InvokeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, mydject.RegisterOptions{Interfaces: ifs, Group: true, LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService3(), mydject.RegisterOptions{Interfaces: ifs, Group: true}); err != nil {
+			t.Fatal(err)
+		}
+		return sut
+	}
+	t.Run("グループのメンバーが登録順に解決されること", func(t *testing.T) {
+		sut := setup(t)
+		var first []Service1
+		if err := sut.Invoke(func(services []Service1) {
+			first = services
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if len(first) != 3 || first[0].GetName() != "service1" || first[1].GetName() != "service2" || first[2].GetName() != "service3" {
+			t.Fatal(first)
+		}
+		if err := sut.Invoke(func(services []Service1) {
+			// メンバーごとのライフタイムスコープが維持されること
+			if services[0].GetID() == first[0].GetID() || services[1].GetID() != first[1].GetID() || services[2].GetID() != first[2].GetID() {
+				t.Fatal()
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("グループの登録は単一の登録を上書きしないこと", func(t *testing.T) {
+		sut := setup(t)
+		if err := sut.Invoke(func(service1 Service1) {}); !mydject.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1, services []Service1) {
+			if len(services) != 3 {
+				t.Fatal(services)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("子コンテナで追加したメンバーは親コンテナに影響しないこと", func(t *testing.T) {
+		container := setup(t)
+		sut := container.CreateChildContainer()
+		if err := sut.Register(NewService1, mydject.RegisterOptions{Group: true, LifetimeScope: mydject.InvokeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if services, err := mydject.Resolve[[]Service1](sut); err != nil || len(services) != 4 {
+			t.Fatal(err)
+		}
+		if services, err := mydject.Resolve[[]Service1](container); err != nil || len(services) != 3 {
+			t.Fatal(err)
+		}
+	})
+	t.Run("グループのメンバーを検証できること", func(t *testing.T) {
+		sut := setup(t)
+		if err := sut.Register(NewNestedService, mydject.RegisterOptions{Group: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(mydject.VerifyOptions{DryRun: true}); !mydject.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_container_MultipleOutputs(t *testing.T) {
+	t.Run("全ての返り値が登録されコンストラクタの呼び出しが共有されること", func(t *testing.T) {
+		for _, lts := range []mydject.LifetimeScope{mydject.ContainerManaged, mydject.InvokeManaged} {
+			sut := mydject.NewContainer()
+			count := 0
+			if err := sut.Register(func() (Service1, Service2, error) {
+				count++
+				service1, service2 := NewService1With2()
+				return service1, service2, nil
+			}, mydject.RegisterOptions{MultipleOutputs: true, LifetimeScope: lts}); err != nil {
+				t.Fatal(err)
+			}
+			if err := sut.Invoke(func(service1 Service1, service2 Service2) {
+				if service1.GetName() != "service1" || service2.GetName() != "service2" {
+					t.Fatal()
+				}
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if count != 1 {
+				t.Fatal(lts, count)
+			}
+		}
+	})
+	t.Run("Interfaces は代入できる返り値に登録されること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		ifs := []reflect.Type{reflect.TypeOf((*Resource)(nil)).Elem()}
+		if err := sut.Register(func() (Service1, *resource) {
+			return NewService1(), &resource{name: "resource"}
+		}, mydject.RegisterOptions{MultipleOutputs: true, Interfaces: ifs}); err != nil {
+			t.Fatal(err)
+		}
+		if r, err := mydject.Resolve[Resource](sut); err != nil || r.Name() != "resource" {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Interfaces を実装している返り値がない場合はエラーになること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		useCaseType := reflect.TypeOf((*UseCase)(nil)).Elem()
+		err := sut.Register(func() (Service1, Service2) {
+			return NewService1(), NewService2()
+		}, mydject.RegisterOptions{MultipleOutputs: true, Interfaces: []reflect.Type{useCaseType}})
+		var ierr *mydject.InterfaceError
+		if !isRegistrationError(err, mydject.ErrNotImplemented) || !errors.As(err, &ierr) || ierr.Interface != useCaseType {
+			t.Fatal(err)
+		}
+	})
+	t.Run("インターフェイスを指定しないポインタの返り値はエラーになること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		err := sut.Register(func() (Service1, *resource) {
+			return NewService1(), &resource{name: "resource"}
+		}, mydject.RegisterOptions{MultipleOutputs: true})
+		if !isRegistrationError(err, mydject.ErrNeedInterfaceOnPointerRegistering) {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1) {}); !mydject.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("コンストラクタがエラーを返した場合は全ての返り値でエラーになること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1With2WithError, mydject.RegisterOptions{MultipleOutputs: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service2 Service2) {}); !isConstructorError(err, "NewService1With2WithError Error") {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_container_CaptiveDependency(t *testing.T) {
+	service2 := func(service1 Service1) Service2 {
+		return NewService2()
+	}
+	singleton := mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}
+	t.Run("CaptiveDependencyFailOnRegister の場合 Register でエラーになり登録されないこと", func(t *testing.T) {
+		sut := mydject.NewContainer(mydject.ContainerOptions{CaptiveDependency: mydject.CaptiveDependencyFailOnRegister})
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Register(service2, singleton)
+		var rerr *mydject.RegistrationError
+		var lerr *mydject.LifetimeViolationError
+		if !errors.As(err, &rerr) || !errors.As(err, &lerr) ||
+			lerr.LifetimeScope != mydject.ContainerManaged || lerr.DependencyLifetimeScope != mydject.InvokeManaged {
+			t.Fatal(err)
+		}
+		if _, err := mydject.Resolve[Service2](sut); !mydject.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("依存される側を後から登録した場合も Register でエラーになること", func(t *testing.T) {
+		sut := mydject.NewContainer(mydject.ContainerOptions{CaptiveDependency: mydject.CaptiveDependencyFailOnRegister})
+		if err := sut.Register(service2, singleton); err != nil {
+			t.Fatal(err)
+		}
+		var lerr *mydject.LifetimeViolationError
+		if err := sut.Register(NewService1, mydject.RegisterOptions{LifetimeScope: mydject.ScopeManaged}); !errors.As(err, &lerr) ||
+			lerr.Dependency != reflect.TypeOf((*Service1)(nil)).Elem() {
+			t.Fatal(err)
+		}
+	})
+	t.Run("後から登録したグループのメンバーとデコレータの依存関係も Register でエラーになること", func(t *testing.T) {
+		sut := mydject.NewContainer(mydject.ContainerOptions{CaptiveDependency: mydject.CaptiveDependencyFailOnRegister})
+		if err := sut.Register(func(services []Service1) Service2 {
+			return NewService2()
+		}, singleton); err != nil {
+			t.Fatal(err)
+		}
+		var lerr *mydject.LifetimeViolationError
+		if err := sut.Register(NewService1, mydject.RegisterOptions{Group: true, LifetimeScope: mydject.InvokeManaged}); !errors.As(err, &lerr) {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1, mydject.RegisterOptions{Group: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService3); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Decorate(func(inner Service2, service3 Service3) Service2 {
+			return inner
+		}); !errors.As(err, &lerr) {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(mydject.VerifyOptions{DryRun: true}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Register でエラーになった場合 生成済みのインスタンスが破棄されないこと", func(t *testing.T) {
+		sut := mydject.NewContainer(mydject.ContainerOptions{CaptiveDependency: mydject.CaptiveDependencyFailOnRegister})
+		if err := sut.Register(NewService2); err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		if err := sut.Register(func() Service1 {
+			count++
+			return NewService1()
+		}, singleton); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := mydject.Resolve[Service1](sut); err != nil {
+			t.Fatal(err)
+		}
+		var lerr *mydject.LifetimeViolationError
+		if err := sut.Register(func(service2 Service2) Service1 {
+			return NewService1()
+		}, singleton); !errors.As(err, &lerr) {
+			t.Fatal(err)
+		}
+		if _, err := mydject.Resolve[Service1](sut); err != nil || count != 1 {
+			t.Fatal(err, count)
+		}
+	})
+	t.Run("CaptiveDependencyWarn の場合 警告が通知されエラーにならないこと", func(t *testing.T) {
+		var warnings []error
+		sut := mydject.NewContainer(mydject.ContainerOptions{
+			CaptiveDependency: mydject.CaptiveDependencyWarn,
+			OnWarning:         func(err error) { warnings = append(warnings, err) },
+		})
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(service2, singleton); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		if len(warnings) != 2 {
+			t.Fatal(warnings)
+		}
+	})
+	t.Run("OnWarning からコンテナを利用できること", func(t *testing.T) {
+		var sut mydject.Container
+		var names []string
+		sut = mydject.NewContainer(mydject.ContainerOptions{
+			CaptiveDependency: mydject.CaptiveDependencyWarn,
+			OnWarning: func(err error) {
+				s, err := mydject.Resolve[Service1](sut)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				names = append(names, s.GetName())
+			},
+		})
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(service2, singleton); err != nil {
+			t.Fatal(err)
+		}
+		if len(names) != 1 || names[0] != "service1" {
+			t.Fatal(names)
+		}
+	})
+	t.Run("Verify で ScopeManaged への依存を検出すること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1, mydject.RegisterOptions{LifetimeScope: mydject.ScopeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(service2, singleton); err != nil {
+			t.Fatal(err)
+		}
+		var lerr *mydject.LifetimeViolationError
+		if err := sut.Verify(); !errors.As(err, &lerr) || lerr.DependencyLifetimeScope != mydject.ScopeManaged {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Transient への依存は検出せず、後処理は要求した側と同じく Close で実行されること", func(t *testing.T) {
+		sut := mydject.NewContainer(mydject.ContainerOptions{CaptiveDependency: mydject.CaptiveDependencyFailOnRegister})
+		cleaned := 0
+		if err := sut.Register(func() (Service1, func()) {
+			return NewService1(), func() { cleaned++ }
+		}, mydject.RegisterOptions{LifetimeScope: mydject.Transient}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(service2, singleton); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(mydject.VerifyOptions{DryRun: true}); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			if err := sut.Invoke(func(service2 Service2, service1 Service1) {}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// Invoke が直接要求した Service1 の後処理のみ Invoke の終了時に実行されること
+		if cleaned != 2 {
+			t.Fatal(cleaned)
+		}
+		if err := sut.Close(); err != nil || cleaned != 3 {
+			t.Fatal(err, cleaned)
+		}
+	})
+}
+func Test_container_Call(t *testing.T) {
+	t.Run("invoker の返り値を error を除いて返すこと", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		outs, err := sut.Call(func(service1 Service1) (string, Service1, error) {
+			return service1.GetName(), service1, nil
+		})
+		if err != nil || len(outs) != 2 || outs[0].String() != "service1" {
+			t.Fatal(outs, err)
+		}
+	})
+	t.Run("解決できない場合", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if _, err := sut.Call(func(service1 Service1) string { return "" }); !mydject.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_container_Variadic(t *testing.T) {
+	service1Type := reflect.TypeOf((*Service1)(nil)).Elem()
+	t.Run("引数のない関数を呼び出せること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		called := false
+		if err := sut.Invoke(func() { called = true }); err != nil || !called {
+			t.Fatal(err)
+		}
+	})
+	t.Run("可変長引数がグループのメンバーに解決されること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		for i := 0; i < 2; i++ {
+			if err := sut.Register(NewService1, mydject.RegisterOptions{Interfaces: []reflect.Type{service1Type}, Group: true, LifetimeScope: mydject.InvokeManaged}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := sut.Register(func(services ...Service1) Service2 {
+			return &service2{id: fmt.Sprint(len(services))}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service2 Service2, services ...Service1) {
+			if len(services) != 2 || service2.GetID() != "2" {
+				t.Fatal(len(services), service2.GetID())
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("グループが存在しない場合は空のスライスに解決されること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(func(services ...Service1) Service2 {
+			return &service2{id: fmt.Sprint(len(services))}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(mydject.VerifyOptions{DryRun: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service2 Service2, services ...Service1) {
+			if services == nil || len(services) != 0 || service2.GetID() != "0" {
+				t.Fatal(services, service2.GetID())
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_container_PanicRecovery(t *testing.T) {
+	t.Run("コンストラクタの panic が ConstructorPanicError になること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(func() Service1 {
+			panic("constructor panic")
+		}, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Invoke(func(service1 Service1) {})
+		var perr *mydject.ConstructorPanicError
+		var rerr *mydject.ResolveError
+		if !errors.As(err, &perr) || !errors.As(err, &rerr) ||
+			perr.Type != reflect.TypeOf((*Service1)(nil)).Elem() || perr.Value != "constructor panic" ||
+			perr.Constructor == "" || len(perr.Stack) == 0 {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Invoke した関数の panic が ConstructorPanicError になること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		e := errors.New("invoker panic")
+		err := sut.Invoke(func() { panic(e) })
+		var perr *mydject.ConstructorPanicError
+		if !errors.As(err, &perr) || !errors.Is(err, e) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("DisablePanicRecovery の場合 panic がそのまま伝播すること", func(t *testing.T) {
+		sut := mydject.NewContainer(mydject.ContainerOptions{DisablePanicRecovery: true})
+		defer func() {
+			if r := recover(); r != "invoker panic" {
+				t.Fatal(r)
+			}
+		}()
+		sut.Invoke(func() { panic("invoker panic") })
+		t.Fatal("panic していません")
+	})
+}
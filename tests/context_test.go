@@ -0,0 +1,84 @@
+package djecttest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ohishikaito/mydject"
+)
+
+type contextKey struct{}
+
+func Test_container_InvokeContext(t *testing.T) {
+	t.Run("context.Context を要求したコンストラクタに ctx が渡されること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(func(ctx context.Context) Service1 {
+			if ctx.Value(contextKey{}) != "value" {
+				t.Fatal(ctx)
+			}
+			return NewService1()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.WithValue(context.Background(), contextKey{}, "value")
+		if err := sut.InvokeContext(ctx, func(service1 Service1, c context.Context) {
+			if c != ctx {
+				t.Fatal(c)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(mydject.VerifyOptions{DryRun: true}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("ctx がキャンセルされた場合 ctx.Err() で解決を中止すること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		ctx, cancel := context.WithCancel(context.Background())
+		called := false
+		if err := sut.Register(func() Service1 {
+			cancel()
+			return NewService1()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(service1 Service1) Service2 {
+			called = true
+			return NewService2()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.InvokeContext(ctx, func(service1 Service1, service2 Service2) {
+			t.Fatal("呼び出されました")
+		})
+		if !errors.Is(err, context.Canceled) || called {
+			t.Fatal(err)
+		}
+	})
+	t.Run("ctx が保持するスコープで解決されること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1, mydject.RegisterOptions{LifetimeScope: mydject.ScopeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		scope := sut.BeginScope()
+		defer scope.Close()
+		id := ""
+		if err := scope.InvokeContext(context.Background(), func(ctx context.Context, service1 Service1) {
+			if mydject.ScopeFromContext(ctx) != scope {
+				t.Fatal("ctx がスコープを保持していません")
+			}
+			id = service1.GetID()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		ctx := mydject.WithScope(context.Background(), scope)
+		if err := sut.InvokeContext(ctx, func(service1 Service1) {
+			if id != service1.GetID() {
+				t.Fatal(id, service1.GetID())
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package djecttest
+
+import (
+	"testing"
+
+	"github.com/ohishikaito/mydject"
+)
+
+type (
+	decoratedService1 struct {
+		Service1
+		name string
+	}
+)
+
+func (s *decoratedService1) GetName() string {
+	return s.name + "(" + s.Service1.GetName() + ")"
+}
+func decorateService1(name string) func(inner Service1) Service1 {
+	return func(inner Service1) Service1 {
+		return &decoratedService1{Service1: inner, name: name}
+	}
+}
+
+func Test_container_Decorate(t *testing.T) {
+	t.Run("デコレータが登録順に適用されること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Decorate(decorateService1("logging")); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Decorate(func(inner Service1, service2 Service2) (Service1, error) {
+			return &decoratedService1{Service1: inner, name: service2.GetName()}, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2); err != nil {
+			t.Fatal(err)
+		}
+		if name := mydject.MustResolve[Service1](sut).GetName(); name != "service2(logging(service1))" {
+			t.Fatal(name)
+		}
+	})
+	t.Run("元の登録のライフタイムスコープに従うこと", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		if err := sut.Decorate(func(inner Service1) Service1 {
+			count++
+			return &decoratedService1{Service1: inner, name: "cache"}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		a := mydject.MustResolve[Service1](sut)
+		b := mydject.MustResolve[Service1](sut)
+		if a != b || count != 1 {
+			t.Fatal(count)
+		}
+	})
+	t.Run("子コンテナで親コンテナの登録にデコレータを追加できること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Decorate(decorateService1("parent")); err != nil {
+			t.Fatal(err)
+		}
+		parent := mydject.MustResolve[Service1](sut)
+		child := sut.CreateChildContainer()
+		if err := child.Decorate(decorateService1("child")); err != nil {
+			t.Fatal(err)
+		}
+		if name := mydject.MustResolve[Service1](child).GetName(); name != "child(parent(service1))" {
+			t.Fatal(name)
+		}
+		if mydject.MustResolve[Service1](child).GetID() != parent.GetID() {
+			t.Fatal("親コンテナのインスタンスが引き継がれていません")
+		}
+		if name := mydject.MustResolve[Service1](sut).GetName(); name != "parent(service1)" {
+			t.Fatal(name)
+		}
+	})
+	t.Run("デコレータの形式が不正な場合", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Decorate(func(inner Service1) Service2 { return nil }); !isRegistrationError(err, mydject.ErrInvalidDecorator) {
+			t.Fatal(err)
+		}
+		if err := sut.Decorate(NewService1()); !isRegistrationError(err, mydject.ErrRequireFunction) {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,230 @@
+package djecttest
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/ohishikaito/mydject"
+)
+
+type (
+	// Resource is
+	Resource interface {
+		Name() string
+		Close() error
+	}
+	resource struct {
+		name   string
+		err    error
+		closed *closedLog
+	}
+	resourceResults struct {
+		mydject.Out
+		Primary   Resource `inject:"name=primary"`
+		Secondary Resource `inject:"name=secondary"`
+	}
+	closedLog struct {
+		mu    sync.Mutex
+		names []string
+	}
+)
+
+func (r *resource) Name() string {
+	return r.name
+}
+func (r *resource) Close() error {
+	r.closed.add(r.name)
+	return r.err
+}
+func (l *closedLog) add(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.names = append(l.names, name)
+}
+
+func Test_container_Close(t *testing.T) {
+	resourceType := reflect.TypeOf((*Resource)(nil)).Elem()
+	register := func(t *testing.T, c mydject.Container, name string, err error, log *closedLog, lts mydject.LifetimeScope) {
+		if err := c.Register(func() Resource {
+			return &resource{name: name, err: err, closed: log}
+		}, mydject.RegisterOptions{Interfaces: []reflect.Type{resourceType}, Name: name, LifetimeScope: lts}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resolve := func(t *testing.T, c mydject.Container, names ...string) {
+		for _, name := range names {
+			if _, err := mydject.ResolveNamed[Resource](c, name); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	t.Run("生成したインスタンスを生成の逆順に破棄すること", func(t *testing.T) {
+		log := &closedLog{}
+		sut := mydject.NewContainer()
+		register(t, sut, "a", nil, log, mydject.ContainerManaged)
+		register(t, sut, "b", nil, log, mydject.ContainerManaged)
+		register(t, sut, "c", nil, log, mydject.ContainerManaged)
+		register(t, sut, "invoke", nil, log, mydject.InvokeManaged)
+		resolve(t, sut, "b", "a", "invoke", "c", "a")
+		if err := sut.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(log.names, []string{"c", "a", "b"}) {
+			t.Fatal(log.names)
+		}
+		if err := sut.Close(); err != nil || len(log.names) != 3 {
+			t.Fatal(err, log.names)
+		}
+	})
+	t.Run("破棄のエラーをまとめて返すこと", func(t *testing.T) {
+		log := &closedLog{}
+		e1 := errors.New("a")
+		e2 := errors.New("b")
+		sut := mydject.NewContainer()
+		register(t, sut, "a", e1, log, mydject.ContainerManaged)
+		register(t, sut, "b", e2, log, mydject.ContainerManaged)
+		resolve(t, sut, "a", "b")
+		err := sut.Close()
+		var cerr *mydject.CloseError
+		if !errors.As(err, &cerr) || len(cerr.Errors) != 2 || !errors.Is(err, e1) || !errors.Is(err, e2) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Out を埋め込んだ構造体の各フィールドを破棄すること", func(t *testing.T) {
+		log := &closedLog{}
+		sut := mydject.NewContainer()
+		if err := sut.Register(func() resourceResults {
+			return resourceResults{
+				Primary:   &resource{name: "primary", closed: log},
+				Secondary: &resource{name: "secondary", closed: log},
+			}
+		}, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		resolve(t, sut, "primary")
+		if err := sut.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(log.names, []string{"secondary", "primary"}) {
+			t.Fatal(log.names)
+		}
+	})
+	t.Run("子コンテナは自身が生成したインスタンスのみ破棄すること", func(t *testing.T) {
+		log := &closedLog{}
+		parent := mydject.NewContainer()
+		register(t, parent, "parent", nil, log, mydject.ContainerManaged)
+		register(t, parent, "lazy", nil, log, mydject.ContainerManaged)
+		resolve(t, parent, "parent")
+		sut := parent.CreateChildContainer()
+		register(t, sut, "child", nil, log, mydject.ContainerManaged)
+		resolve(t, sut, "parent", "lazy", "child")
+		if err := sut.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(log.names, []string{"child", "lazy"}) {
+			t.Fatal(log.names)
+		}
+		if err := parent.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(log.names, []string{"child", "lazy", "parent"}) {
+			t.Fatal(log.names)
+		}
+	})
+}
+func Test_container_Cleanup(t *testing.T) {
+	t.Run("InvokeManaged の後処理は Invoke の終了時に逆順で実行されること", func(t *testing.T) {
+		log := &closedLog{}
+		sut := mydject.NewContainer()
+		if err := sut.Register(func(service2 Service2) (Service1, func()) {
+			return NewService1(), func() { log.add("service1") }
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func() (Service2, func() error, error) {
+			return NewService2(), func() error { log.add("service2"); return nil }, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1) {
+			if len(log.names) != 0 {
+				t.Fatal(log.names)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(log.names, []string{"service1", "service2"}) {
+			t.Fatal(log.names)
+		}
+	})
+	t.Run("解決に失敗した場合も生成済みのインスタンスの後処理が実行されること", func(t *testing.T) {
+		log := &closedLog{}
+		e := errors.New("cleanup error")
+		sut := mydject.NewContainer()
+		if err := sut.Register(func() (Service1, func() error) {
+			return NewService1(), func() error { log.add("service1"); return e }
+		}); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Invoke(func(service1 Service1, service2 Service2) {})
+		if !mydject.IsErrInvalidResolveComponent(err) || !errors.Is(err, e) || !reflect.DeepEqual(log.names, []string{"service1"}) {
+			t.Fatal(err, log.names)
+		}
+	})
+	t.Run("ContainerManaged の後処理はコンテナの Close で実行されること", func(t *testing.T) {
+		log := &closedLog{}
+		sut := mydject.NewContainer()
+		if err := sut.Register(func() (Resource, func()) {
+			return &resource{name: "resource", closed: log}, func() { log.add("cleanup") }
+		}, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(r Resource) {}); err != nil {
+			t.Fatal(err)
+		}
+		if len(log.names) != 0 {
+			t.Fatal(log.names)
+		}
+		if err := sut.Close(); err != nil {
+			t.Fatal(err)
+		}
+		// 後処理の関数が返された場合は io.Closer は呼び出されないこと
+		if !reflect.DeepEqual(log.names, []string{"cleanup"}) {
+			t.Fatal(log.names)
+		}
+	})
+	t.Run("Invoke の終了後に Provider で生成したインスタンスの後処理はスコープまたはコンテナの Close で実行されること", func(t *testing.T) {
+		log := &closedLog{}
+		sut := mydject.NewContainer()
+		if err := sut.Register(func() (Service1, func()) {
+			return NewService1(), func() { log.add("service1") }
+		}); err != nil {
+			t.Fatal(err)
+		}
+		var provider mydject.Provider[Service1]
+		if err := sut.Invoke(func(p mydject.Provider[Service1]) { provider = p }); err != nil {
+			t.Fatal(err)
+		}
+		scope := sut.BeginScope()
+		var scoped mydject.Provider[Service1]
+		if err := scope.Invoke(func(p mydject.Provider[Service1]) { scoped = p }); err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range []mydject.Provider[Service1]{provider, provider, scoped} {
+			if _, err := p.Get(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if len(log.names) != 0 {
+			t.Fatal(log.names)
+		}
+		if err := scope.Close(); err != nil || len(log.names) != 1 {
+			t.Fatal(err, log.names)
+		}
+		if err := sut.Close(); err != nil || len(log.names) != 3 {
+			t.Fatal(err, log.names)
+		}
+	})
+}
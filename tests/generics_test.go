@@ -0,0 +1,370 @@
+package djecttest
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/ohishikaito/mydject"
+)
+
+func Test_Resolve(t *testing.T) {
+	t.Run("型を指定して解決できること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		service1, err := mydject.Resolve[Service1](sut)
+		if err != nil || service1.GetName() != "service1" {
+			t.Fatal(err)
+		}
+		if mydject.MustResolve[Service1](sut).GetID() == service1.GetID() {
+			t.Fatal()
+		}
+	})
+	t.Run("解決できない場合はエラーを返すこと", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if _, err := mydject.Resolve[Service1](sut); !mydject.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("MustResolve は解決できない場合 panic すること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		defer func() {
+			if err, ok := recover().(error); !ok || !mydject.IsErrInvalidResolveComponent(err) {
+				t.Fatal(err)
+			}
+		}()
+		mydject.MustResolve[Service1](sut)
+	})
+}
+func Test_Provide(t *testing.T) {
+	t.Run("コンストラクタをインターフェイスとして登録できること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := mydject.Provide[Service1](sut, NewService1, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if mydject.MustResolve[Service1](sut).GetID() != mydject.MustResolve[Service1](sut).GetID() {
+			t.Fatal()
+		}
+	})
+	t.Run("オプションを指定しない場合は InvokeManaged で登録されること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := mydject.Provide[Service1](sut, NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if mydject.MustResolve[Service1](sut).GetID() == mydject.MustResolve[Service1](sut).GetID() {
+			t.Fatal()
+		}
+	})
+	t.Run("値をインターフェイスとして登録できること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		service3 := NewService3()
+		if err := mydject.ProvideValue(sut, service3); err != nil {
+			t.Fatal(err)
+		}
+		if mydject.MustResolve[Service3](sut) != service3 {
+			t.Fatal()
+		}
+	})
+	t.Run("代入できない型を指定した場合はエラーを返すこと", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		err := mydject.Provide[UseCase](sut, NewService1)
+		var rerr *mydject.RegistrationError
+		if !errors.As(err, &rerr) || !errors.Is(err, mydject.ErrNotAssignable) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("nil を指定した場合はエラーを返すこと", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		var constructor func() Service1
+		if err := mydject.Provide[Service1](sut, constructor); !isRegistrationError(err, mydject.ErrNilTarget) {
+			t.Fatal(err)
+		}
+		if err := mydject.ProvideValue[Service1](sut, nil); !isRegistrationError(err, mydject.ErrNilTarget) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("As はインターフェイスの型を返すこと", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		service3 := NewService3()
+		if err := sut.Register(service3, mydject.RegisterOptions{Interfaces: []reflect.Type{mydject.As[Service3]()}}); err != nil {
+			t.Fatal(err)
+		}
+		if mydject.MustResolve[Service3](sut) != service3 {
+			t.Fatal()
+		}
+	})
+}
+func Test_Optional(t *testing.T) {
+	t.Run("登録が存在しない場合は false を返すこと", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(func(service2 mydject.Optional[Service2]) Service1 {
+			if _, ok := service2.Get(); ok {
+				t.Fatal()
+			}
+			return NewService1()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1, service3 mydject.Optional[Service3]) {
+			if s, ok := service3.Get(); ok || s != nil {
+				t.Fatal(s)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(mydject.VerifyOptions{DryRun: true}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("登録が存在する場合は解決されること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 mydject.Optional[Service1], s Service1) {
+			if v, ok := service1.Get(); !ok || v != s {
+				t.Fatal(v)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("登録が存在して解決に失敗した場合はエラーになること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewNestedService); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(nestedService mydject.Optional[NestedService]) {}); !mydject.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_Lazy(t *testing.T) {
+	t.Run("最初の Get で解決されキャッシュされること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		count := 0
+		if err := sut.Register(func() Service1 {
+			count++
+			return NewService1()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(lazy mydject.Lazy[Service1]) {
+			if count != 0 {
+				t.Fatal(count)
+			}
+			s1, err := lazy.Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			s2, err := lazy.Get()
+			if err != nil || s1 != s2 || count != 1 {
+				t.Fatal(err, count)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Invoke の中では InvokeManaged な T が引数と同じインスタンスになること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1, lazy mydject.Lazy[Service1]) {
+			s, err := lazy.Get()
+			if err != nil || s.GetID() != service1.GetID() {
+				t.Fatal(err)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("コンストラクタの中で自身を Get した場合は循環参照になること", func(t *testing.T) {
+		for _, lts := range []mydject.LifetimeScope{mydject.InvokeManaged, mydject.ContainerManaged} {
+			sut := mydject.NewContainer()
+			if err := sut.Register(func(a mydject.Lazy[CircularA]) (CircularA, error) {
+				if _, err := a.Get(); err != nil {
+					return nil, err
+				}
+				return &struct{}{}, nil
+			}, mydject.RegisterOptions{LifetimeScope: lts}); err != nil {
+				t.Fatal(err)
+			}
+			var cerr *mydject.CircularDependencyError
+			if err := sut.Invoke(func(a CircularA) {}); !errors.As(err, &cerr) {
+				t.Fatal(lts, err)
+			}
+		}
+	})
+	t.Run("循環参照を Lazy で解消できること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(func(b mydject.Lazy[CircularB]) CircularA {
+			return &struct{ b mydject.Lazy[CircularB] }{b}
+		}, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(a CircularA) CircularB {
+			return &struct{ a CircularA }{a}
+		}, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(mydject.VerifyOptions{DryRun: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(a CircularA) {
+			if _, err := a.(*struct{ b mydject.Lazy[CircularB] }).b.Get(); err != nil {
+				t.Fatal(err)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("ContainerManaged なインスタンスが保持する Lazy を複数の goroutine から Get できること", func(t *testing.T) {
+		type holder struct {
+			s1 mydject.Lazy[Service1]
+			s2 mydject.Lazy[Service2]
+		}
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(s1 mydject.Lazy[Service1], s2 mydject.Lazy[Service2]) holder {
+			return holder{s1: s1, s2: s2}
+		}, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		h, err := mydject.Resolve[holder](sut)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				if _, err := h.s1.Get(); err != nil {
+					t.Error(err)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				if _, err := h.s2.Get(); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+	t.Run("Invoke の中で複数の goroutine から Get できること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(a, b mydject.Lazy[Service1], c mydject.Lazy[Service2]) {
+			ids := make([]string, 2)
+			var wg sync.WaitGroup
+			for i, lazy := range []mydject.Lazy[Service1]{a, b} {
+				wg.Add(1)
+				go func(i int, lazy mydject.Lazy[Service1]) {
+					defer wg.Done()
+					s, err := lazy.Get()
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					ids[i] = s.GetID()
+				}(i, lazy)
+			}
+			if _, err := c.Get(); err != nil {
+				t.Error(err)
+			}
+			wg.Wait()
+			if ids[0] != ids[1] {
+				t.Error(ids)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_Provider(t *testing.T) {
+	t.Run("Get のたびに InvokeManaged なインスタンスが生成されること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, mydject.RegisterOptions{LifetimeScope: mydject.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(p1 mydject.Provider[Service1], p2 mydject.Provider[Service2]) {
+			a, err := p1.Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := p1.Get()
+			if err != nil || a.GetID() == b.GetID() {
+				t.Fatal(err)
+			}
+			c, _ := p2.Get()
+			d, _ := p2.Get()
+			if c.GetID() != d.GetID() {
+				t.Fatal()
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("func() (T, error) で解決できること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(newService1 func() (Service1, error), newService2 func() (Service2, error)) {
+			if s, err := newService1(); err != nil || s.GetName() != "service1" {
+				t.Fatal(err)
+			}
+			if _, err := newService2(); !mydject.IsErrInvalidResolveComponent(err) {
+				t.Fatal(err)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_InvokeR(t *testing.T) {
+	sut := mydject.NewContainer()
+	if err := sut.Register(NewService1); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("invoker の返り値を返すこと", func(t *testing.T) {
+		name, err := mydject.InvokeR[string](sut, func(service1 Service1) string {
+			return service1.GetName()
+		})
+		if err != nil || name != "service1" {
+			t.Fatal(name, err)
+		}
+	})
+	t.Run("invoker のエラーを返すこと", func(t *testing.T) {
+		e := errors.New("invoker error")
+		name, err := mydject.InvokeR[string](sut, func(service1 Service1) (string, error) {
+			return service1.GetName(), e
+		})
+		if err != e || name != "service1" {
+			t.Fatal(name, err)
+		}
+	})
+	t.Run("返り値の型が異なる場合", func(t *testing.T) {
+		if _, err := mydject.InvokeR[int](sut, func(service1 Service1) string { return "" }); !errors.Is(err, mydject.ErrNotAssignable) {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package djecttest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ohishikaito/mydject"
+)
+
+type (
+	injected struct {
+		Service1 Service1 `inject:""`
+		Primary  Service2 `inject:"name=primary"`
+		Service3 Service3 `inject:"optional"`
+		NotTag   Service1
+	}
+	injectedStruct1 struct {
+		Service1 Service1 `inject:""`
+	}
+	injectedUnexported struct {
+		service1 Service1 `inject:""`
+	}
+	injectedInvalid struct {
+		Service1 Service1 `inject:"unknown"`
+	}
+)
+
+func Test_container_InjectInto(t *testing.T) {
+	setup := func(t *testing.T) mydject.Container {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, mydject.RegisterOptions{Name: "primary"}); err != nil {
+			t.Fatal(err)
+		}
+		return sut
+	}
+	t.Run("inject タグの付いたフィールドに注入されること", func(t *testing.T) {
+		sut := setup(t)
+		var s injected
+		if err := sut.InjectInto(&s); err != nil {
+			t.Fatal(err)
+		}
+		if s.Service1.GetName() != "service1" || s.Primary.GetName() != "service2" || s.Service3 != nil || s.NotTag != nil {
+			t.Fatal(s)
+		}
+	})
+	t.Run("optional でないフィールドが解決できない場合はエラーになること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		var s injected
+		if err := sut.InjectInto(&s); !mydject.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("不正な指定はエラーになること", func(t *testing.T) {
+		sut := setup(t)
+		if err := sut.InjectInto(injected{}); !errors.Is(err, mydject.ErrRequireStruct) {
+			t.Fatal(err)
+		}
+		if err := sut.InjectInto(&injectedUnexported{}); !errors.Is(err, mydject.ErrUnexportedInjectField) {
+			t.Fatal(err)
+		}
+		if err := sut.InjectInto(&injectedInvalid{}); !errors.Is(err, mydject.ErrInvalidInjectTag) {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_container_Register_Struct(t *testing.T) {
+	t.Run("構造体を登録してフィールドに注入されること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, mydject.RegisterOptions{Name: "primary"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(mydject.Struct[injected](), mydject.RegisterOptions{LifetimeScope: mydject.InvokeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(s injected, service1 Service1) {
+			if s.Service1 != service1 || s.Primary.GetName() != "service2" {
+				t.Fatal(s)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(mydject.VerifyOptions{DryRun: true}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("構造体のポインタをインターフェイスとして登録できること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		type holder interface{}
+		ifs := []reflect.Type{reflect.TypeOf((*holder)(nil)).Elem()}
+		if err := sut.Register(mydject.Struct[*injectedStruct1](), mydject.RegisterOptions{Interfaces: ifs}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(h holder) {
+			if h.(*injectedStruct1).Service1.GetName() != "service1" {
+				t.Fatal(h)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("構造体以外はエラーになること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(mydject.Struct[Service1]()); !isRegistrationError(err, mydject.ErrRequireStruct) {
+			t.Fatal(err)
+		}
+		if err := sut.Register(mydject.Struct[injectedInvalid]()); !isRegistrationError(err, mydject.ErrInvalidInjectTag) {
+			t.Fatal(err)
+		}
+	})
+}
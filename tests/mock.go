@@ -197,3 +197,63 @@ func NewService1With2() (Service1, Service2) {
 func NewService1With2WithError() (Service1, Service2, error) {
 	return &service1{id: uuid.New().String(), name: "service1"}, &service2{id: uuid.New().String(), name: "service2"}, errors.New("NewService1With2WithError Error")
 }
+
+type (
+	// CircularA is
+	CircularA interface{}
+	// CircularB is
+	CircularB interface{}
+	// CircularC is
+	CircularC interface{}
+)
+
+// NewCircularA is
+func NewCircularA(b CircularB) CircularA {
+	return &struct{ b CircularB }{b}
+}
+
+// NewCircularB is
+func NewCircularB(c CircularC) CircularB {
+	return &struct{ c CircularC }{c}
+}
+
+// NewCircularC is
+func NewCircularC(a CircularA) CircularC {
+	return &struct{ a CircularA }{a}
+}
+
+//go:generate go run ../cmd/mydject-proxy -type Service1,Greeter -output mock_proxy.go
+
+type (
+	// Greeter is
+	Greeter interface {
+		Greet(name string, suffixes ...string) (string, error)
+		Reset()
+	}
+	greeter struct {
+		count int
+	}
+)
+
+// NewGreeter is
+func NewGreeter() Greeter {
+	return &greeter{}
+}
+
+// Greet is
+func (g *greeter) Greet(name string, suffixes ...string) (string, error) {
+	if name == "" {
+		return "", errors.New("name is empty")
+	}
+	g.count++
+	s := "hello " + name
+	for _, suffix := range suffixes {
+		s += suffix
+	}
+	return s, nil
+}
+
+// Reset is
+func (g *greeter) Reset() {
+	g.count = 0
+}
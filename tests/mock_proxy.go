@@ -0,0 +1,56 @@
+// Code generated by mydject-proxy. DO NOT EDIT.
+
+package djecttest
+
+import (
+	"github.com/ohishikaito/mydject"
+)
+
+// service1Proxy は Service1 のメソッド呼び出しをインターセプタに渡すプロキシです
+type service1Proxy struct {
+	handler *mydject.ProxyHandler
+}
+
+func init() {
+	mydject.RegisterProxy[Service1](func(h *mydject.ProxyHandler) Service1 {
+		return &service1Proxy{handler: h}
+	})
+}
+
+// GetID は Service1.GetID の呼び出しをインターセプタに渡します
+func (p *service1Proxy) GetID() string {
+	results := p.handler.Call("GetID")
+	r0, _ := results[0].(string)
+	return r0
+}
+
+// GetName は Service1.GetName の呼び出しをインターセプタに渡します
+func (p *service1Proxy) GetName() string {
+	results := p.handler.Call("GetName")
+	r0, _ := results[0].(string)
+	return r0
+}
+
+// greeterProxy は Greeter のメソッド呼び出しをインターセプタに渡すプロキシです
+type greeterProxy struct {
+	handler *mydject.ProxyHandler
+}
+
+func init() {
+	mydject.RegisterProxy[Greeter](func(h *mydject.ProxyHandler) Greeter {
+		return &greeterProxy{handler: h}
+	})
+}
+
+// Greet は Greeter.Greet の呼び出しをインターセプタに渡します
+func (p *greeterProxy) Greet(a0 string, a1 ...string) (string, error) {
+	results := p.handler.Call("Greet", a0, a1)
+	r0, _ := results[0].(string)
+	r1, _ := results[1].(error)
+	return r0, r1
+}
+
+// Reset は Greeter.Reset の呼び出しをインターセプタに渡します
+func (p *greeterProxy) Reset() {
+	p.handler.Call("Reset")
+}
@@ -0,0 +1,92 @@
+package djecttest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ohishikaito/mydject"
+)
+
+type (
+	useCaseParams struct {
+		mydject.In
+		Service1 Service1
+		Primary  Service2   `inject:"name=primary"`
+		Service3 Service3   `inject:"optional"`
+		Services []Service1 `inject:"group"`
+	}
+	serviceResults struct {
+		mydject.Out
+		Service1 Service1
+		Primary  Service2 `inject:"name=primary"`
+		Member   Service1 `inject:"group"`
+	}
+	invalidParams struct {
+		mydject.In
+		service1 Service1
+	}
+)
+
+func Test_container_In(t *testing.T) {
+	t.Run("In を埋め込んだ構造体の各フィールドが解決されること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, mydject.RegisterOptions{Name: "primary"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(p useCaseParams) UseCase {
+			return NewUseCase(nil, p.Service1, p.Primary, p.Service3)
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(p useCaseParams, useCase UseCase) {
+			if p.Service1 == nil || p.Primary.GetName() != "service2" || p.Service3 != nil || p.Services == nil || len(p.Services) != 0 {
+				t.Fatal(p)
+			}
+			if useCase.GetService1() != p.Service1 {
+				t.Fatal()
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(mydject.VerifyOptions{DryRun: true}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("公開されていないフィールドはエラーになること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		if err := sut.Register(func(p invalidParams) Service1 { return nil }); !isRegistrationError(err, mydject.ErrUnexportedInjectField) {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(p invalidParams) {}); !errors.Is(err, mydject.ErrUnexportedInjectField) {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_container_Out(t *testing.T) {
+	t.Run("Out を埋め込んだ構造体の各フィールドが登録されること", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		count := 0
+		if err := sut.Register(func() (serviceResults, error) {
+			count++
+			return serviceResults{Service1: NewService1(), Primary: NewService2(), Member: NewService1()}, nil
+		}, mydject.RegisterOptions{LifetimeScope: mydject.InvokeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(p useCaseParams) {
+			if p.Service1 == nil || p.Primary.GetName() != "service2" || len(p.Services) != 1 || p.Services[0] == p.Service1 {
+				t.Fatal(p)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			t.Fatal(count)
+		}
+		if _, err := mydject.Resolve[serviceResults](sut); !mydject.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package djecttest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ohishikaito/mydject"
+)
+
+func Test_container_Interceptors(t *testing.T) {
+	greeterType := reflect.TypeOf((*Greeter)(nil)).Elem()
+	t.Run("全てのメソッドの呼び出しにインターセプタが適用されること", func(t *testing.T) {
+		var calls []string
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewGreeter, mydject.RegisterOptions{
+			Interfaces: []reflect.Type{greeterType},
+			Interceptors: []mydject.Interceptor{{
+				Before: func(method string, args []interface{}) {
+					calls = append(calls, "before "+method)
+				},
+				After: func(method string, args []interface{}, results []interface{}) {
+					calls = append(calls, "after "+method)
+				},
+			}, {
+				Around: func(method string, args []interface{}, proceed func() []interface{}) []interface{} {
+					calls = append(calls, "around "+method)
+					results := proceed()
+					if method == "Greet" {
+						results[0] = strings.ToUpper(results[0].(string))
+					}
+					return results
+				},
+			}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		greeter := mydject.MustResolve[Greeter](sut)
+		s, err := greeter.Greet("world", "!", "?")
+		if err != nil || s != "HELLO WORLD!?" {
+			t.Fatal(s, err)
+		}
+		if _, err := greeter.Greet(""); err == nil {
+			t.Fatal("エラーが返されていません")
+		}
+		greeter.Reset()
+		want := []string{
+			"before Greet", "around Greet", "after Greet",
+			"before Greet", "around Greet", "after Greet",
+			"before Reset", "around Reset", "after Reset",
+		}
+		if !reflect.DeepEqual(calls, want) {
+			t.Fatal(calls)
+		}
+	})
+	t.Run("フックがメソッドの引数を受け取ること", func(t *testing.T) {
+		var got []interface{}
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1, mydject.RegisterOptions{
+			Interfaces:    []reflect.Type{reflect.TypeOf((*Service1)(nil)).Elem()},
+			LifetimeScope: mydject.ContainerManaged,
+			Interceptors: []mydject.Interceptor{{
+				Before: func(method string, args []interface{}) {
+					got = append(got, method)
+				},
+			}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		a := mydject.MustResolve[Service1](sut)
+		b := mydject.MustResolve[Service1](sut)
+		if a != b || a.GetName() != "service1" || !reflect.DeepEqual(got, []interface{}{"GetName"}) {
+			t.Fatal(got)
+		}
+	})
+	t.Run("プロキシが生成されていないインターフェイスの場合", func(t *testing.T) {
+		sut := mydject.NewContainer()
+		err := sut.Register(NewService2, mydject.RegisterOptions{
+			Interfaces:   []reflect.Type{reflect.TypeOf((*Service2)(nil)).Elem()},
+			Interceptors: []mydject.Interceptor{{}},
+		})
+		if !isRegistrationError(err, mydject.ErrProxyNotFound) {
+			t.Fatal(err)
+		}
+	})
+}
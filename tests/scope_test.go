@@ -0,0 +1,88 @@
+package djecttest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ohishikaito/mydject"
+)
+
+func Test_container_BeginScope(t *testing.T) {
+	resourceType := reflect.TypeOf((*Resource)(nil)).Elem()
+	setup := func(t *testing.T, log *closedLog) mydject.Container {
+		sut := mydject.NewContainer()
+		if err := sut.Register(NewService1, mydject.RegisterOptions{LifetimeScope: mydject.ScopeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func() Resource {
+			return &resource{name: "scoped", closed: log}
+		}, mydject.RegisterOptions{Interfaces: []reflect.Type{resourceType}, LifetimeScope: mydject.ScopeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		return sut
+	}
+	t.Run("スコープ内の呼び出しでインスタンスが共有されること", func(t *testing.T) {
+		sut := setup(t, &closedLog{})
+		scope1 := sut.BeginScope()
+		defer scope1.Close()
+		scope2 := sut.BeginScope()
+		defer scope2.Close()
+		a := mydject.MustResolve[Service1](scope1)
+		b := mydject.MustResolve[Service1](scope1)
+		c := mydject.MustResolve[Service1](scope2)
+		if a.GetID() != b.GetID() {
+			t.Fatal("スコープ内でインスタンスが異なります")
+		}
+		if a.GetID() == c.GetID() {
+			t.Fatal("スコープ間でインスタンスが共有されています")
+		}
+	})
+	t.Run("Close でスコープのインスタンスが破棄されること", func(t *testing.T) {
+		log := &closedLog{}
+		sut := setup(t, log)
+		scope := sut.BeginScope()
+		mydject.MustResolve[Resource](scope)
+		if len(log.names) != 0 {
+			t.Fatal(log.names)
+		}
+		if err := scope.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(log.names, []string{"scoped"}) {
+			t.Fatal(log.names)
+		}
+		if err := sut.Close(); err != nil || len(log.names) != 1 {
+			t.Fatal(err, log.names)
+		}
+	})
+	t.Run("スコープの外では解決できないこと", func(t *testing.T) {
+		sut := setup(t, &closedLog{})
+		_, err := mydject.Resolve[Service1](sut)
+		if !errors.Is(err, mydject.ErrRequireScope) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("スコープの中では ServiceLocator としてスコープが解決されること", func(t *testing.T) {
+		sut := setup(t, &closedLog{})
+		scope := sut.BeginScope()
+		defer scope.Close()
+		if err := scope.Invoke(func(l mydject.ServiceLocator, s Service1) {
+			if mydject.MustResolve[Service1](l).GetID() != s.GetID() {
+				t.Fatal("スコープが解決されていません")
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Verify で ScopeManaged なコンポーネントが検証されること", func(t *testing.T) {
+		log := &closedLog{}
+		sut := setup(t, log)
+		if err := sut.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(log.names, []string{"scoped"}) {
+			t.Fatal(log.names)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package mydject
+
+// verifyStatic はコンストラクタを呼び出さずに、登録された依存関係の不足、循環参照、ライフタイムスコープの違反を全て検出します
+func (c *container) verifyStatic() error {
+	c.mu.RLock()
+	registry := c.registry.clone()
+	c.mu.RUnlock()
+	if registry.isEmpty() {
+		return c.localize(ErrNotFoundComponent)
+	}
+	keys := registry.keys()
+
+	var errs []error
+	edges := make(map[key][]key, len(keys))
+	for _, k := range keys {
+		f, _ := registry.get(k)
+		for _, p := range registry.params(k, f) {
+			if c.isBuiltinType(p.key.t) {
+				continue
+			}
+			deps, ok, deferred := c.dependencyKeys(registry, p.key)
+			if p.group {
+				// グループはメンバーが存在しない場合も空のスライスとして解決できます
+				deps, _ = registry.members(p.key)
+				ok = true
+			}
+			if !ok {
+				if p.optional {
+					continue
+				}
+				errs = append(errs, &ResolveError{
+					Type: p.key.t,
+					Name: p.key.name,
+					Path: []Dependency{newDependency(k, f), newDependency(p.key, factoryInfo{})},
+					Err:  c.localize(ErrInvalidResolveComponent),
+					lang: c.options.Language,
+				})
+				continue
+			}
+			if deferred {
+				continue
+			}
+			edges[k] = append(edges[k], deps...)
+		}
+	}
+	errs = append(errs, c.verifyLifetimes(registry)...)
+	for _, cycle := range findCycles(keys, edges) {
+		deps := make([]Dependency, len(cycle))
+		for i, k := range cycle {
+			f, _ := registry.get(k)
+			deps[i] = newDependency(k, f)
+		}
+		errs = append(errs, &CircularDependencyError{Path: deps, lang: c.options.Language})
+	}
+	if len(errs) > 0 {
+		return &VerifyError{Errors: errs, lang: c.options.Language}
+	}
+	return nil
+}
+
+// dependencyKeys は要求されたキーを解決するときに使われる登録のキーを返します。
+// deferred の場合は解決が遅延されるため、循環参照とライフタイムスコープの検証の対象外です
+func (c *container) dependencyKeys(registry *registry, k key) (deps []key, ok bool, deferred bool) {
+	if _, ok := registry.get(k); ok {
+		return []key{k}, true, false
+	}
+	if members, ok := registry.members(k); ok {
+		return members, true, false
+	}
+	w, ok := asWrapper(k.t)
+	if !ok {
+		w, ok = asProviderFunc(k.t)
+	}
+	if ok {
+		inner := w.wrappedKey(k)
+		if c.isBuiltinType(inner.t) {
+			return nil, true, true
+		}
+		deps, ok, deferred := c.dependencyKeys(registry, inner)
+		return deps, ok || w.isOptional(), deferred || w.isDeferred()
+	}
+	return nil, false, false
+}
+
+// findCycles は深さ優先探索で依存関係の循環を全て探します。各循環は先頭と末尾が同じコンポーネントの経路で返されます
+func findCycles(keys []key, edges map[key][]key) [][]key {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[key]int, len(keys))
+	var path []key
+	var cycles [][]key
+	var visit func(k key)
+	visit = func(k key) {
+		state[k] = visiting
+		path = append(path, k)
+		for _, in := range edges[k] {
+			switch state[in] {
+			case unvisited:
+				visit(in)
+			case visiting:
+				for i, p := range path {
+					if p == in {
+						cycle := append(append([]key{}, path[i:]...), in)
+						cycles = append(cycles, cycle)
+						break
+					}
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[k] = visited
+	}
+	for _, k := range keys {
+		if state[k] == unvisited {
+			visit(k)
+		}
+	}
+	return cycles
+}
@@ -0,0 +1,9 @@
+package mydject
+
+type (
+	// VerifyOptions は検証時のオプションです
+	VerifyOptions struct {
+		// DryRun の場合、コンストラクタを呼び出さずに登録内容のみから依存関係を検証します
+		DryRun bool
+	}
+)